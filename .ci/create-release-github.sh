@@ -7,4 +7,5 @@ gh config set prompt disabled
 gh release create \
     -t "Release ${OPERATOR_VERSION}" \
     "${OPERATOR_VERSION}" \
-    'dist/opentelemetry-operator.yaml#Installation manifest for Kubernetes'
+    'dist/opentelemetry-operator.yaml#Installation manifest for Kubernetes' \
+    'dist/opentelemetry-operator-self-managed-certs.yaml#Installation manifest for Kubernetes, without cert-manager'
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+)
+
+// WebhookCertificates tells who provides the certificates of the operator's webhooks.
+type WebhookCertificates string
+
+const (
+	// WebhookCertificatesCertManager means that the certificates are issued by cert-manager.
+	WebhookCertificatesCertManager WebhookCertificates = "cert-manager"
+
+	// WebhookCertificatesSelfManaged means that the operator issues and renews the certificates itself.
+	WebhookCertificatesSelfManaged WebhookCertificates = "self-managed"
+)
+
+// +kubebuilder:object:root=true
+
+// OperatorConfiguration is the schema of the operator's configuration file. The collector defaults, except for the
+// distributions, the image rules, the namespace selector and the auto-detection frequency are reloaded when the file
+// changes, the other settings require a restart.
+type OperatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManagerConfigurationSpec holds the settings of the controller manager, like the metrics endpoint,
+	// the leader election and the webhook server.
+	cfg.ControllerManagerConfigurationSpec `json:",inline"`
+
+	// WatchNamespaces are the namespaces whose instances are managed by the operator. All the namespaces are
+	// watched when empty.
+	// +optional
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces whose instances are managed, and whose pods get sidecars, among the
+	// watched namespaces. The namespaces are selected again when their labels change, and all of them are selected
+	// when empty.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// WebhookCertificates tells who provides the certificates of the operator's webhooks. Defaults to cert-manager.
+	// +optional
+	WebhookCertificates WebhookCertificates `json:"webhookCertificates,omitempty"`
+
+	// AutoDetectFrequency is how often the platform and the capabilities of the cluster are detected.
+	// +optional
+	AutoDetectFrequency *metav1.Duration `json:"autoDetectFrequency,omitempty"`
+
+	// Collector holds the defaults for the OpenTelemetry Collector instances.
+	// +optional
+	Collector CollectorDefaults `json:"collector,omitempty"`
+
+	// Images holds the rules applied to all the images of the containers set by the operator.
+	// +optional
+	Images ImageSettings `json:"images,omitempty"`
+
+	// Features toggles the optional features of the operator.
+	// +optional
+	Features Features `json:"features,omitempty"`
+}
+
+// CollectorDefaults holds the settings applied to the OpenTelemetry Collector instances that don't set them.
+type CollectorDefaults struct {
+	// Image is the default image of the collector, for the modes without an image of their own.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Images are the default images of the collector per mode.
+	// +optional
+	Images CollectorImages `json:"images,omitempty"`
+
+	// Resources are the default compute resources of the collector container.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Labels are added to the workloads and the pods of the collectors. The labels of the instances take precedence.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the workloads and the pods of the collectors. The annotations of the instances take
+	// precedence.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ConfigMapEntry is the name of the entry holding the collector's configuration in its config map.
+	// +optional
+	ConfigMapEntry string `json:"configMapEntry,omitempty"`
+
+	// Distributions is the path to a file with additional OpenTelemetry Collector distributions.
+	// +optional
+	Distributions string `json:"distributions,omitempty"`
+}
+
+// ImageSettings holds the rules applied to all the images of the containers set by the operator, like for clusters
+// that can only pull images from a private registry.
+type ImageSettings struct {
+	// Rewrites replace the prefix of the images. The prefixes are matched against the fully qualified image
+	// references, like docker.io/otel/opentelemetry-collector:0.31.0, and the first matching rule applies.
+	// +optional
+	Rewrites []ImageRewrite `json:"rewrites,omitempty"`
+
+	// Digests pin the images to a digest, like sha256:0123..., keyed by the fully qualified image reference after
+	// the rewrites.
+	// +optional
+	Digests map[string]string `json:"digests,omitempty"`
+
+	// PullSecrets are the image pull secrets added to the pods of all the collectors.
+	// +optional
+	PullSecrets []corev1.LocalObjectReference `json:"pullSecrets,omitempty"`
+}
+
+// ImageRewrite replaces a prefix of the images.
+type ImageRewrite struct {
+	// From is the prefix to replace, like docker.io/otel/.
+	From string `json:"from"`
+
+	// To is the replacement of the prefix, like registry.example.com/mirror/otel/.
+	To string `json:"to"`
+}
+
+// CollectorImages holds the default images of the collector per mode.
+type CollectorImages struct {
+	// +optional
+	Deployment string `json:"deployment,omitempty"`
+
+	// +optional
+	DaemonSet string `json:"daemonset,omitempty"`
+
+	// +optional
+	StatefulSet string `json:"statefulset,omitempty"`
+
+	// +optional
+	Sidecar string `json:"sidecar,omitempty"`
+}
+
+// Features toggles the optional features of the operator. All of them are enabled by default.
+type Features struct {
+	// Webhooks enables the webhooks defaulting and validating the instances, and injecting the sidecars.
+	// +optional
+	Webhooks *bool `json:"webhooks,omitempty"`
+
+	// SidecarInjection enables the injection of sidecars into the pods. It requires the webhooks.
+	// +optional
+	SidecarInjection *bool `json:"sidecarInjection,omitempty"`
+
+	// Upgrades enables the upgrade of the managed instances when the operator starts.
+	// +optional
+	Upgrades *bool `json:"upgrades,omitempty"`
+}
+
+// Enabled returns whether the given feature is enabled, which is the case unless it has been explicitly disabled.
+func Enabled(feature *bool) bool {
+	return feature == nil || *feature
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfiguration{})
+}
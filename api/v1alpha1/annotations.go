@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+const (
+	// AnnotationPaused is the annotation that pauses the reconciliation of an instance when set to "true". While paused,
+	// the managed objects can be changed by hand without being reverted by the operator.
+	AnnotationPaused = "opentelemetry.io/paused"
+
+	// AnnotationDefaultImage holds the image recorded by the operator in the spec of an instance created without an
+	// explicit image. While the image in the spec matches it, the image follows the operator's default image, according
+	// to the instance's upgrade strategy.
+	AnnotationDefaultImage = "opentelemetry.io/default-image"
+)
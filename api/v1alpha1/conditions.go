@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+const (
+	// ConditionDegraded is the condition type set when one or more reconciliation tasks are failing for the instance.
+	ConditionDegraded = "Degraded"
+
+	// ConditionPaused is the condition type set when the reconciliation of the instance has been paused.
+	ConditionPaused = "Paused"
+
+	// ConditionProbesEnabled is the condition type telling whether the collector container has liveness and readiness
+	// probes, which require the health_check extension.
+	ConditionProbesEnabled = "ProbesEnabled"
+)
+
+const (
+	// ReasonTaskFailed is used when at least one of the reconciliation tasks has failed.
+	ReasonTaskFailed = "TaskFailed"
+
+	// ReasonTasksSucceeded is used when all the reconciliation tasks have succeeded.
+	ReasonTasksSucceeded = "TasksSucceeded"
+
+	// ReasonPaused is used when the reconciliation has been paused via the AnnotationPaused annotation.
+	ReasonPaused = "ReconciliationPaused"
+
+	// ReasonResumed is used when the reconciliation has been resumed after being paused.
+	ReasonResumed = "ReconciliationResumed"
+
+	// ReasonHealthCheckEnabled is used when the probes use the health_check extension of the configuration.
+	ReasonHealthCheckEnabled = "HealthCheckEnabled"
+
+	// ReasonHealthCheckMissing is used when the probes are disabled, as the health_check extension isn't enabled in
+	// the configuration.
+	ReasonHealthCheckMissing = "HealthCheckMissing"
+
+	// ReasonSidecarMode is used when the probes are disabled, as the collector runs as a sidecar of the applications.
+	ReasonSidecarMode = "SidecarMode"
+)
@@ -0,0 +1,30 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+// HostPortSpec exposes a port of the collector on the nodes running it, so that the applications can send their
+// telemetry to the collector on their own node.
+type HostPortSpec struct {
+	// Name is the name of the port, as listed in the collector's service, like otlp-grpc for the gRPC protocol of the
+	// OTLP receiver, or one of the names from the attribute 'ports'.
+	// +required
+	Name string `json:"name"`
+
+	// HostPort is the port opened on the nodes. Defaults to the port of the receiver.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	HostPort int32 `json:"hostPort,omitempty"`
+}
@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // OpenTelemetryCollectorSpec defines the desired state of OpenTelemetryCollector.
@@ -36,11 +37,32 @@ type OpenTelemetryCollectorSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	Replicas *int32 `json:"replicas,omitempty"`
 
-	// Image indicates the container image to use for the OpenTelemetry Collector.
+	// Image indicates the container image to use for the OpenTelemetry Collector. When not set, the operator's
+	// default image is recorded here when the instance is created.
 	// +optional
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	Image string `json:"image,omitempty"`
 
+	// ImagePullPolicy indicates the pull policy of the OpenTelemetry Collector image. When not set, Kubernetes decides
+	// based on the image tag.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets are the secrets used to pull the images of the collector's pods, in addition to the operator's
+	// default pull secrets.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Distribution is the name of the OpenTelemetry Collector distribution of the image, like core or contrib. The
+	// configuration is validated against the components included in the distribution. When not set, the distribution
+	// of the image is recorded here when the instance is created, if known by the operator.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	Distribution string `json:"distribution,omitempty"`
+
 	// Mode represents how the collector should be deployed (deployment, daemonset, statefulset or sidecar)
 	// +optional
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
@@ -82,6 +104,12 @@ type OpenTelemetryCollectorSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	Ports []v1.ServicePort `json:"ports,omitempty"`
 
+	// HostPorts exposes ports of the collector on the nodes, like the ports of its receivers. Only supported in the
+	// daemonset mode, whose service also routes the traffic to the collector on the client's node.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	HostPorts []HostPortSpec `json:"hostPorts,omitempty"`
+
 	// ENV vars to set on the OpenTelemetry Collector's Pods. These can then in certain cases be
 	// consumed in the config file for the Collector.
 	// +optional
@@ -98,6 +126,64 @@ type OpenTelemetryCollectorSpec struct {
 	// +optional
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// InitContainers are run before the OpenTelemetry Collector, like to fetch parts of its configuration. They can
+	// mount the operator-managed configuration volume, named otc-internal. Not supported in the sidecar mode.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	InitContainers []v1.Container `json:"initContainers,omitempty"`
+
+	// AdditionalContainers are run next to the OpenTelemetry Collector, like to ship its logs. They can mount the
+	// operator-managed configuration volume, named otc-internal. Not supported in the sidecar mode.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	AdditionalContainers []v1.Container `json:"additionalContainers,omitempty"`
+
+	// PodTemplateOverlay is a strategic merge patch applied to the pod template built by the operator, for the pod
+	// fields not modeled by this spec, like host aliases or sysctls. In the sidecar mode, it can only change the injected
+	// container and add volumes, as the rest of the pod belongs to the application. It can't remove the operator-managed
+	// container or its configuration volume.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	PodTemplateOverlay *runtime.RawExtension `json:"podTemplateOverlay,omitempty"`
+
+	// UpgradePolicy controls whether the operator applies the changes required by new OpenTelemetry Collector versions
+	// to the configuration (automatic), only proposes them in the status (manual), or leaves the instance alone (none).
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// UpgradeStrategy controls whether the image recorded by the operator for this instance is replaced by the operator's
+	// default image when the operator is upgraded (automatic), or stays pinned (none). Images set explicitly in the
+	// spec are never replaced.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// TLS enables the certificates managed by the operator for the receivers. The certificates are mounted in the
+	// collector's pods, and the receivers are configured to use them.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// Route enables the OpenShift routes exposing the receivers, one per receiver port. It's ignored on other
+	// platforms.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	Route *RouteSpec `json:"route,omitempty"`
+
+	// LivenessProbe overrides the settings of the liveness probe of the collector container. The probe is only set
+	// when the health_check extension is enabled in the configuration, and never for the sidecars.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	LivenessProbe *ProbeSpec `json:"livenessProbe,omitempty"`
+
+	// ReadinessProbe overrides the settings of the readiness probe of the collector container. The probe is only set
+	// when the health_check extension is enabled in the configuration, and never for the sidecars.
+	// +optional
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	ReadinessProbe *ProbeSpec `json:"readinessProbe,omitempty"`
 }
 
 // OpenTelemetryCollectorStatus defines the observed state of OpenTelemetryCollector.
@@ -106,14 +192,77 @@ type OpenTelemetryCollectorStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
 
-	// Version of the managed OpenTelemetry Collector (operand)
+	// Version of the managed OpenTelemetry Collector (operand), based on the image running in its pods.
 	// +optional
 	Version string `json:"version,omitempty"`
 
 	// Messages about actions performed by the operator on this resource.
+	// Deprecated: the changes made by the upgrades are recorded in UpgradeHistory. This is kept for the
+	// messages recorded by older versions of the operator.
 	// +optional
 	// +listType=atomic
 	Messages []string `json:"messages,omitempty"`
+
+	// UpgradeHistory records the most recent upgrade steps applied to this resource, oldest first.
+	// +optional
+	// +listType=atomic
+	UpgradeHistory []UpgradeHistoryEntry `json:"upgradeHistory,omitempty"`
+
+	// PendingUpgrade describes the upgrade proposed for this resource when its upgrade policy is manual.
+	// +optional
+	PendingUpgrade *PendingUpgrade `json:"pendingUpgrade,omitempty"`
+
+	// TLS describes the serving certificate used by the receivers, when enabled.
+	// +optional
+	TLS *TLSStatus `json:"tls,omitempty"`
+
+	// Drift lists the differences between the desired objects and the ones in the cluster, reported while the
+	// reconciliation is paused. These are the changes to be made once the reconciliation is resumed.
+	// +optional
+	// +listType=atomic
+	Drift []string `json:"drift,omitempty"`
+
+	// Conditions represent the latest available observations of the instance's state, like whether the
+	// reconciliation of the managed objects is failing.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// UpgradeHistoryEntry describes an upgrade step applied to an instance.
+type UpgradeHistoryEntry struct {
+	// FromVersion is the version of the instance before the upgrade step.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version of the instance after the upgrade step.
+	ToVersion string `json:"toVersion"`
+
+	// Timestamp is the time when the upgrade step was applied.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Changes describes the changes made to the instance's configuration by the upgrade step.
+	// +optional
+	// +listType=atomic
+	Changes []string `json:"changes,omitempty"`
+}
+
+// PendingUpgrade describes the changes that an upgrade would make to an instance, without applying them.
+type PendingUpgrade struct {
+	// FromVersion is the current version of the instance.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version the instance would be upgraded to.
+	ToVersion string `json:"toVersion"`
+
+	// Changes describes the changes the upgrade would make to the instance's configuration.
+	// +optional
+	// +listType=atomic
+	Changes []string `json:"changes,omitempty"`
+
+	// ConfigDiff is a unified diff between the current and the proposed configuration.
+	// +optional
+	ConfigDiff string `json:"configDiff,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -15,18 +15,67 @@
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	defaultTLSDuration    = 90 * 24 * time.Hour
+	defaultTLSRenewBefore = 30 * 24 * time.Hour
+
+	// minTLSDuration leaves room for the renewal of the certificates, as the instances are only guaranteed to be
+	// reconciled when the informers' caches are resynchronized, every ten hours by default
+	minTLSDuration = 24 * time.Hour
 )
 
 // log is for logging in this package.
 var opentelemetrycollectorlog = logf.Log.WithName("opentelemetrycollector-resource")
 
-func (r *OpenTelemetryCollector) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// WebhookOptions holds the operator's settings used by the webhooks.
+// +kubebuilder:object:generate=false
+type WebhookOptions struct {
+	// CollectorImage returns the image recorded in the spec of the new instances in the given mode without an
+	// explicit image.
+	CollectorImage func(mode Mode) string
+
+	// Distribution returns the name of the distribution of the given image, recorded in the spec of the new instances
+	// without an explicit distribution. It returns an empty string for unknown images.
+	Distribution func(image string) string
+
+	// Validate returns an error when the instance isn't valid according to the operator's settings, like when its
+	// configuration uses components that aren't included in its distribution.
+	Validate func(r *OpenTelemetryCollector) error
+
+	// InScope returns whether the instances in the given namespace are managed by the operator. The instances out of
+	// scope are neither defaulted nor validated, as another operator might manage them.
+	InScope func(namespace string) bool
+
+	// Warnings returns the warnings about the valid instances sent back to the clients creating or updating them,
+	// like when the instance won't have probes.
+	Warnings func(r *OpenTelemetryCollector) []string
+}
+
+// webhookOptions holds the options the webhooks have been registered with.
+var webhookOptions = WebhookOptions{}
+
+// SetupWebhookWithManager registers the webhooks for the type, using the given operator's settings.
+func (r *OpenTelemetryCollector) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	webhookOptions = opts
+
+	// the validating webhook is registered first, so that the builder doesn't register its own without the warnings
+	mgr.GetWebhookServer().Register(validatingWebhookPath, &webhook.Admission{
+		Handler: &warningsHandler{validator: admission.ValidatingWebhookFor(r).Handler},
+	})
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -38,10 +87,56 @@ var _ webhook.Defaulter = &OpenTelemetryCollector{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type.
 func (r *OpenTelemetryCollector) Default() {
+	if !r.inScope() {
+		return
+	}
+
 	if len(r.Spec.Mode) == 0 {
 		r.Spec.Mode = ModeDeployment
 	}
 
+	if len(r.Spec.UpgradePolicy) == 0 {
+		r.Spec.UpgradePolicy = UpgradePolicyAutomatic
+	}
+
+	if len(r.Spec.UpgradeStrategy) == 0 {
+		r.Spec.UpgradeStrategy = UpgradeStrategyAutomatic
+	}
+
+	// the image and its distribution are resolved only once, when the instance is created, so that upgrading the
+	// operator doesn't change them
+	if r.CreationTimestamp.IsZero() {
+		if len(r.Spec.Image) == 0 && webhookOptions.CollectorImage != nil {
+			if image := webhookOptions.CollectorImage(r.Spec.Mode); len(image) > 0 {
+				if r.Annotations == nil {
+					r.Annotations = map[string]string{}
+				}
+				r.Spec.Image = image
+				r.Annotations[AnnotationDefaultImage] = image
+			}
+		}
+
+		if len(r.Spec.Distribution) == 0 && webhookOptions.Distribution != nil {
+			r.Spec.Distribution = webhookOptions.Distribution(r.Spec.Image)
+		}
+	}
+
+	if r.Spec.TLS != nil {
+		if len(r.Spec.TLS.Issuer) == 0 {
+			r.Spec.TLS.Issuer = TLSIssuerAuto
+		}
+		if r.Spec.TLS.Duration == nil {
+			r.Spec.TLS.Duration = &metav1.Duration{Duration: defaultTLSDuration}
+		}
+		if r.Spec.TLS.RenewBefore == nil {
+			r.Spec.TLS.RenewBefore = &metav1.Duration{Duration: defaultTLSRenewBefore}
+		}
+	}
+
+	if r.Spec.Route != nil && len(r.Spec.Route.Termination) == 0 {
+		r.Spec.Route.Termination = TLSRouteTerminationTypeEdge
+	}
+
 	if r.Labels == nil {
 		r.Labels = map[string]string{}
 	}
@@ -52,6 +147,10 @@ func (r *OpenTelemetryCollector) Default() {
 	opentelemetrycollectorlog.Info("default", "name", r.Name)
 }
 
+// validatingWebhookPath is the path of the validating webhook for the creations and updates, as generated by the
+// webhook builder.
+const validatingWebhookPath = "/validate-opentelemetry-io-v1alpha1-opentelemetrycollector"
+
 // +kubebuilder:webhook:verbs=create;update,path=/validate-opentelemetry-io-v1alpha1-opentelemetrycollector,mutating=false,failurePolicy=fail,groups=opentelemetry.io,resources=opentelemetrycollectors,versions=v1alpha1,name=vopentelemetrycollectorcreateupdate.kb.io,sideEffects=none,admissionReviewVersions=v1;v1beta1
 // +kubebuilder:webhook:verbs=delete,path=/validate-opentelemetry-io-v1alpha1-opentelemetrycollector,mutating=false,failurePolicy=ignore,groups=opentelemetry.io,resources=opentelemetrycollectors,versions=v1alpha1,name=vopentelemetrycollectordelete.kb.io,sideEffects=none,admissionReviewVersions=v1;v1beta1
 
@@ -60,12 +159,18 @@ var _ webhook.Validator = &OpenTelemetryCollector{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *OpenTelemetryCollector) ValidateCreate() error {
 	opentelemetrycollectorlog.Info("validate create", "name", r.Name)
+	if !r.inScope() {
+		return nil
+	}
 	return r.validateCRDSpec()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
 func (r *OpenTelemetryCollector) ValidateUpdate(old runtime.Object) error {
 	opentelemetrycollectorlog.Info("validate update", "name", r.Name)
+	if !r.inScope() {
+		return nil
+	}
 	return r.validateCRDSpec()
 }
 
@@ -75,6 +180,43 @@ func (r *OpenTelemetryCollector) ValidateDelete() error {
 	return nil
 }
 
+// inScope returns whether the instance is managed by the operator, according to the webhook options.
+func (r *OpenTelemetryCollector) inScope() bool {
+	return webhookOptions.InScope == nil || webhookOptions.InScope(r.Namespace)
+}
+
+// warningsHandler adds the warnings from the webhook options to the responses of the validating webhook, as the
+// webhook.Validator interface has no way to return them.
+type warningsHandler struct {
+	validator admission.Handler
+	decoder   *admission.Decoder
+}
+
+var _ admission.Handler = &warningsHandler{}
+var _ admission.DecoderInjector = &warningsHandler{}
+
+// Handle validates the instance, adding the warnings about it when it's allowed.
+func (h *warningsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := h.validator.Handle(ctx, req)
+	if !resp.Allowed || req.Operation == admissionv1.Delete || webhookOptions.Warnings == nil || h.decoder == nil {
+		return resp
+	}
+
+	otelcol := &OpenTelemetryCollector{}
+	if err := h.decoder.Decode(req, otelcol); err != nil || !otelcol.inScope() {
+		return resp
+	}
+
+	return resp.WithWarnings(webhookOptions.Warnings(otelcol)...)
+}
+
+// InjectDecoder injects the decoder, into the validator too.
+func (h *warningsHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	_, err := admission.InjectDecoderInto(d, h.validator)
+	return err
+}
+
 func (r *OpenTelemetryCollector) validateCRDSpec() error {
 	// validate volumeClaimTemplates
 	if r.Spec.Mode != ModeStatefulSet && len(r.Spec.VolumeClaimTemplates) > 0 {
@@ -91,5 +233,60 @@ func (r *OpenTelemetryCollector) validateCRDSpec() error {
 		return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'tolerations'", r.Spec.Mode)
 	}
 
+	// validate the containers
+	if r.Spec.Mode == ModeSidecar && len(r.Spec.InitContainers) > 0 {
+		return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'initContainers'", r.Spec.Mode)
+	}
+	if r.Spec.Mode == ModeSidecar && len(r.Spec.AdditionalContainers) > 0 {
+		return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'additionalContainers'", r.Spec.Mode)
+	}
+
+	// validate host ports
+	if r.Spec.Mode != ModeDaemonSet && len(r.Spec.HostPorts) > 0 {
+		return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'hostPorts'", r.Spec.Mode)
+	}
+	hostPortNames := map[string]bool{}
+	for _, hostPort := range r.Spec.HostPorts {
+		if hostPortNames[hostPort.Name] {
+			return fmt.Errorf("the port %q is listed more than once in the attribute 'hostPorts'", hostPort.Name)
+		}
+		hostPortNames[hostPort.Name] = true
+	}
+
+	// validate tls
+	if r.Spec.TLS != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'tls'", r.Spec.Mode)
+		}
+
+		if r.Spec.TLS.Duration != nil && r.Spec.TLS.Duration.Duration < minTLSDuration {
+			return fmt.Errorf("the TLS certificates' duration has to be at least %s", minTLSDuration)
+		}
+
+		if r.Spec.TLS.Duration != nil && r.Spec.TLS.RenewBefore != nil && r.Spec.TLS.RenewBefore.Duration >= r.Spec.TLS.Duration.Duration {
+			return fmt.Errorf("the TLS certificates have to be renewed before their expiry, but 'renewBefore' (%s) isn't shorter than 'duration' (%s)", r.Spec.TLS.RenewBefore.Duration, r.Spec.TLS.Duration.Duration)
+		}
+	}
+
+	// validate route
+	if r.Spec.Route != nil {
+		if r.Spec.Mode == ModeSidecar {
+			return fmt.Errorf("the OpenTelemetry Collector mode is set to %s, which does not support the attribute 'route'", r.Spec.Mode)
+		}
+
+		if r.Spec.Route.Termination == TLSRouteTerminationTypePassthrough && r.Spec.TLS == nil {
+			return fmt.Errorf("the routes with the %s termination require the receivers to use TLS, enable it with the attribute 'tls'", r.Spec.Route.Termination)
+		}
+	}
+
+	// validate probes
+	if r.Spec.LivenessProbe != nil && r.Spec.LivenessProbe.SuccessThreshold != nil && *r.Spec.LivenessProbe.SuccessThreshold != 1 {
+		return fmt.Errorf("the liveness probe's 'successThreshold' has to be 1, got %d", *r.Spec.LivenessProbe.SuccessThreshold)
+	}
+
+	if webhookOptions.Validate != nil {
+		return webhookOptions.Validate(r)
+	}
+
 	return nil
 }
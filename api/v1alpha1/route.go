@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+type (
+	// TLSRouteTerminationType is used to indicate which TLS termination is used by the OpenShift routes
+	// +kubebuilder:validation:Enum=edge;passthrough
+	TLSRouteTerminationType string
+)
+
+const (
+	// TLSRouteTerminationTypeEdge terminates the TLS connections at the OpenShift router, which talks to the
+	// receivers in plain text.
+	TLSRouteTerminationTypeEdge TLSRouteTerminationType = "edge"
+
+	// TLSRouteTerminationTypePassthrough passes the TLS connections through the OpenShift router, the receivers
+	// terminating them with their own certificate.
+	TLSRouteTerminationTypePassthrough TLSRouteTerminationType = "passthrough"
+)
+
+// RouteSpec configures the OpenShift routes exposing the receivers of an instance.
+type RouteSpec struct {
+	// Termination indicates the TLS termination of the routes. Defaults to edge.
+	// +optional
+	Termination TLSRouteTerminationType `json:"termination,omitempty"`
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type (
+	// TLSIssuer represents who issues the certificates for the receivers of an instance
+	// +kubebuilder:validation:Enum=auto;operator;cert-manager;service-ca
+	TLSIssuer string
+)
+
+const (
+	// TLSIssuerAuto specifies that the certificates are issued by the service CA on OpenShift, requested from
+	// cert-manager when it's available in the cluster, and generated by the operator otherwise.
+	TLSIssuerAuto TLSIssuer = "auto"
+
+	// TLSIssuerOperator specifies that the certificates are generated by the operator, with a certificate authority
+	// of its own for each instance.
+	TLSIssuerOperator TLSIssuer = "operator"
+
+	// TLSIssuerCertManager specifies that the certificates are requested from cert-manager.
+	TLSIssuerCertManager TLSIssuer = "cert-manager"
+
+	// TLSIssuerServiceCA specifies that the certificates are issued by OpenShift's service CA operator. The
+	// certificates are only valid for the names of the instance's main service.
+	TLSIssuerServiceCA TLSIssuer = "service-ca"
+)
+
+// TLSSpec configures the certificates managed by the operator for the receivers of an instance.
+type TLSSpec struct {
+	// Issuer controls who issues the certificates: the operator, cert-manager, OpenShift's service CA, or the most
+	// suitable one for the cluster (auto).
+	// +optional
+	Issuer TLSIssuer `json:"issuer,omitempty"`
+
+	// Receivers lists the receivers to secure. When empty, all the receivers supporting TLS are secured.
+	// +optional
+	// +listType=atomic
+	Receivers []string `json:"receivers,omitempty"`
+
+	// AdditionalDNSNames are added to the names covered by the serving certificate, in addition to the names of the
+	// instance's services.
+	// +optional
+	// +listType=atomic
+	AdditionalDNSNames []string `json:"additionalDNSNames,omitempty"`
+
+	// Duration is the validity of the serving certificates. Defaults to 90 days.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore is how long before their expiry the serving certificates are renewed. Defaults to 30 days.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// TLSStatus describes the serving certificate used by the receivers of an instance.
+type TLSStatus struct {
+	// Issuer is who issued the current certificate.
+	// +optional
+	Issuer TLSIssuer `json:"issuer,omitempty"`
+
+	// NotAfter is the expiry of the current certificate.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Fingerprint is the SHA-256 fingerprint of the current certificate. The pods are restarted when it changes.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+type (
+	// UpgradePolicy represents how the operator handles the upgrades of an instance to a new OpenTelemetry Collector version
+	// +kubebuilder:validation:Enum=automatic;manual;none
+	UpgradePolicy string
+
+	// UpgradeStrategy represents whether the image of an instance follows the operator's default image when the operator is upgraded
+	// +kubebuilder:validation:Enum=automatic;none
+	UpgradeStrategy string
+)
+
+const (
+	// UpgradePolicyAutomatic specifies that the changes required by a new version are applied to the instance's configuration.
+	UpgradePolicyAutomatic UpgradePolicy = "automatic"
+
+	// UpgradePolicyManual specifies that the changes required by a new version are only proposed in the instance's status,
+	// leaving the configuration untouched. The instance is considered upgraded once its configuration requires no changes.
+	UpgradePolicyManual UpgradePolicy = "manual"
+
+	// UpgradePolicyNone specifies that the instance is never upgraded by the operator.
+	UpgradePolicyNone UpgradePolicy = "none"
+
+	// UpgradeStrategyAutomatic specifies that the image recorded by the operator for the instance is replaced by the operator's
+	// default image when the operator is upgraded.
+	UpgradeStrategyAutomatic UpgradeStrategy = "automatic"
+
+	// UpgradeStrategyNone specifies that the instance stays pinned to its image, regardless of the operator's version.
+	UpgradeStrategyNone UpgradeStrategy = "none"
+)
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// printDiff compares the rendered objects with the live ones, printing a unified diff for each object that would be
+// changed by the operator. Only the fields set by the operator are compared, as the live objects have extra fields,
+// like the ones with default values, or set by other actors.
+func printDiff(out io.Writer, rendered []map[string]interface{}, live []unstructured.Unstructured) error {
+	byID := map[string]unstructured.Unstructured{}
+	for _, obj := range live {
+		byID[objectID(obj)] = obj
+	}
+
+	instances := map[string]bool{}
+	seen := map[string]bool{}
+	for _, r := range rendered {
+		desired := unstructured.Unstructured{Object: r}
+		id := objectID(desired)
+		seen[id] = true
+		instances[desired.GetLabels()["app.kubernetes.io/instance"]] = true
+
+		after, err := sigsyaml.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", id, err)
+		}
+
+		before := []byte{}
+		existing, found := byID[id]
+		if found {
+			before, err = sigsyaml.Marshal(intersect(r, existing.Object))
+			if err != nil {
+				return fmt.Errorf("failed to marshal the live %s: %w", id, err)
+			}
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(before)),
+			B:        difflib.SplitLines(string(after)),
+			FromFile: "live/" + id,
+			ToFile:   "rendered/" + id,
+			Context:  3,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compare %s: %w", id, err)
+		}
+
+		switch {
+		case !found:
+			fmt.Fprintf(out, "# %s would be created\n%s", id, diff)
+		case len(diff) > 0:
+			fmt.Fprintf(out, "# %s would be updated\n%s", id, diff)
+		default:
+			fmt.Fprintf(out, "# %s is unchanged\n", id)
+		}
+	}
+
+	for _, obj := range live {
+		id := objectID(obj)
+		labels := obj.GetLabels()
+		if seen[id] || labels["app.kubernetes.io/managed-by"] != "opentelemetry-operator" || !instances[labels["app.kubernetes.io/instance"]] {
+			continue
+		}
+		fmt.Fprintf(out, "# %s would be deleted\n", id)
+	}
+
+	return nil
+}
+
+// objectID identifies an object by its kind, namespace and name, regardless of the API version.
+func objectID(obj unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// intersect returns the parts of the live value that are also set in the desired value. Lists with a different
+// number of elements are returned as they are.
+func intersect(desired, live interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		result := map[string]interface{}{}
+		for k, v := range d {
+			if lv, found := l[k]; found {
+				result[k] = intersect(v, lv)
+			}
+		}
+		return result
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		result := make([]interface{}, len(l))
+		for i := range l {
+			result[i] = intersect(d[i], l[i])
+		}
+		return result
+	default:
+		return live
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrintDiff(t *testing.T) {
+	// prepare
+	rendered, err := renderFiles(renderOptions{files: []string{"testdata/simplest.yaml"}, namespace: "default"}, nil)
+	require.NoError(t, err)
+
+	live := []unstructured.Unstructured{}
+	for _, obj := range rendered {
+		u := unstructured.Unstructured{Object: obj}
+		u = *u.DeepCopy()
+		// fields set by the API server shouldn't be part of the diff
+		u.SetResourceVersion("1")
+		if u.GetKind() == "ConfigMap" {
+			require.NoError(t, unstructured.SetNestedField(u.Object, "receivers: {}", "data", "collector.yaml"))
+		}
+		if u.GetKind() == "ServiceAccount" {
+			continue
+		}
+		live = append(live, u)
+	}
+	extra := unstructured.Unstructured{}
+	extra.SetKind("DaemonSet")
+	extra.SetNamespace("default")
+	extra.SetName("simplest-collector")
+	extra.SetLabels(map[string]string{
+		"app.kubernetes.io/instance":   "default.simplest",
+		"app.kubernetes.io/managed-by": "opentelemetry-operator",
+	})
+	live = append(live, extra)
+	out := &bytes.Buffer{}
+
+	// test
+	err = printDiff(out, rendered, live)
+
+	// verify
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "# ConfigMap/default/simplest-collector would be updated\n")
+	assert.Contains(t, out.String(), "-  collector.yaml: 'receivers: {}'\n")
+	assert.Contains(t, out.String(), "# ServiceAccount/default/simplest-collector would be created\n")
+	assert.Contains(t, out.String(), "# Deployment/default/simplest-collector is unchanged\n")
+	assert.Contains(t, out.String(), "# DaemonSet/default/simplest-collector would be deleted\n")
+	assert.NotContains(t, out.String(), "resourceVersion")
+}
+
+func TestIntersect(t *testing.T) {
+	desired := map[string]interface{}{
+		"a": "b",
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http"},
+		},
+	}
+	live := map[string]interface{}{
+		"a": "c",
+		"d": "e",
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http", "protocol": "TCP"},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"a": "c",
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http"},
+		},
+	}
+	assert.Equal(t, expected, intersect(desired, live))
+}
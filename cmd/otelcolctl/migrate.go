@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/version"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/upgrade"
+)
+
+type migrateOptions struct {
+	files  []string
+	from   string
+	to     string
+	config bool
+}
+
+// migrated is the result of the migration of a single document.
+type migrated struct {
+	changes []string
+	content []byte
+}
+
+// migrate upgrades the OpenTelemetryCollector resources, or raw collector configurations, from the files to the
+// target version, printing them along with the changes that have been made.
+func migrate(args []string, out io.Writer) error {
+	opts := migrateOptions{}
+	fs := pflag.NewFlagSet("migrate", pflag.ContinueOnError)
+	fs.StringSliceVarP(&opts.files, "filename", "f", nil, "The files with the OpenTelemetryCollector resources or the collector configurations, or - for the standard input")
+	fs.StringVar(&opts.from, "from", "", "The version to migrate from, defaults to the version in the status of each resource")
+	fs.StringVar(&opts.to, "to", defaultTargetVersion(), "The version to migrate to")
+	fs.BoolVar(&opts.config, "config", false, "Whether the files contain raw collector configurations, instead of OpenTelemetryCollector resources")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(opts.files) == 0 {
+		return errors.New("at least one file has to be specified with --filename")
+	}
+
+	results, err := migrateFiles(opts, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		fmt.Fprintln(out, "---")
+		for _, change := range res.changes {
+			fmt.Fprintf(out, "# %s\n", change)
+		}
+		if _, err := out.Write(res.content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultTargetVersion is the collector version used by this build, or the latest version with an upgrade routine
+// for builds without version information.
+func defaultTargetVersion() string {
+	if v := version.OpenTelemetryCollector(); v != "0.0.0" {
+		return v
+	}
+	return upgrade.Latest.String()
+}
+
+func migrateFiles(opts migrateOptions, stdin io.Reader) ([]migrated, error) {
+	target, err := semver.NewVersion(opts.to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target version %q: %w", opts.to, err)
+	}
+
+	if opts.config {
+		if len(opts.from) == 0 {
+			return nil, errors.New("the version to migrate from has to be specified with --from for collector configurations")
+		}
+		return migrateConfigs(opts, target, stdin)
+	}
+
+	objects, err := readObjects(opts.files, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []migrated{}
+	for _, u := range objects {
+		res, err := migrateObject(u, opts.from, target)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// migrateObject upgrades the configuration of an OpenTelemetryCollector resource, leaving the rest of the resource,
+// and other kinds of objects, untouched.
+func migrateObject(u unstructured.Unstructured, from string, target *semver.Version) (migrated, error) {
+	res := migrated{}
+	if u.GroupVersionKind() == v1alpha1.GroupVersion.WithKind("OpenTelemetryCollector") {
+		instance, err := toInstance(u, "")
+		if err != nil {
+			return res, err
+		}
+
+		upgraded, err := upgradeInstance(instance, from, target)
+		if err != nil {
+			return res, err
+		}
+
+		res.changes = changes(instance.Name, upgraded)
+		if err := unstructured.SetNestedField(u.Object, upgraded.Spec.Config, "spec", "config"); err != nil {
+			return res, fmt.Errorf("failed to set the upgraded configuration for %s: %w", instance.Name, err)
+		}
+
+		// keep the version in the status consistent with the configuration, when the file has one
+		if _, found, _ := unstructured.NestedString(u.Object, "status", "version"); found {
+			if err := unstructured.SetNestedField(u.Object, upgraded.Status.Version, "status", "version"); err != nil {
+				return res, fmt.Errorf("failed to set the upgraded version for %s: %w", instance.Name, err)
+			}
+		}
+	}
+
+	content, err := sigsyaml.Marshal(u.Object)
+	if err != nil {
+		return res, fmt.Errorf("failed to marshal %s: %w", u.GetName(), err)
+	}
+	res.content = content
+
+	return res, nil
+}
+
+func migrateConfigs(opts migrateOptions, target *semver.Version, stdin io.Reader) ([]migrated, error) {
+	results := []migrated{}
+	for _, file := range opts.files {
+		var (
+			content []byte
+			err     error
+		)
+		if file == "-" {
+			content, err = ioutil.ReadAll(stdin)
+		} else {
+			content, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		instance := v1alpha1.OpenTelemetryCollector{}
+		instance.Name = file
+		if file == "-" {
+			instance.Name = "stdin"
+		}
+		instance.Spec.Config = string(content)
+		upgraded, err := upgradeInstance(instance, opts.from, target)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, migrated{
+			changes: changes(file, upgraded),
+			content: []byte(upgraded.Spec.Config),
+		})
+	}
+
+	return results, nil
+}
+
+func upgradeInstance(instance v1alpha1.OpenTelemetryCollector, from string, target *semver.Version) (v1alpha1.OpenTelemetryCollector, error) {
+	if len(from) > 0 {
+		instance.Status.Version = from
+	}
+	if len(instance.Status.Version) == 0 {
+		return instance, fmt.Errorf("the version of %s is unknown, the version to migrate from has to be specified with --from", instance.Name)
+	}
+	// only the changes made by this migration should be reported
+	instance.Status.UpgradeHistory = nil
+
+	upgraded, err := upgrade.Instance(ctrl.Log.WithName("migrate"), instance, target)
+	if err != nil {
+		return upgraded, fmt.Errorf("failed to migrate %s: %w", instance.Name, err)
+	}
+
+	return upgraded, nil
+}
+
+func changes(name string, upgraded v1alpha1.OpenTelemetryCollector) []string {
+	changes := []string{}
+	for _, step := range upgraded.Status.UpgradeHistory {
+		for _, change := range step.Changes {
+			changes = append(changes, fmt.Sprintf("%s: %s", name, change))
+		}
+	}
+
+	if len(changes) == 0 {
+		return []string{fmt.Sprintf("%s: no changes required", name)}
+	}
+	return changes
+}
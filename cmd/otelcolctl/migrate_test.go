@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateResources(t *testing.T) {
+	// prepare
+	opts := migrateOptions{files: []string{"testdata/old.yaml"}, to: "0.24.0"}
+
+	// test
+	results, err := migrateFiles(opts, nil)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Len(t, results[0].changes, 2)
+	assert.Contains(t, results[0].changes[1], "old: upgrade to v0.24.0 migrated the property 'port' to 'endpoint'")
+	assert.Contains(t, string(results[0].content), "endpoint: 0.0.0.0:13133")
+	assert.Contains(t, string(results[0].content), "version: 0.24.0")
+}
+
+func TestMigrateResourcesWithoutVersion(t *testing.T) {
+	// prepare
+	opts := migrateOptions{files: []string{"testdata/simplest.yaml"}, to: "0.24.0"}
+
+	// test
+	_, err := migrateFiles(opts, nil)
+
+	// verify
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--from")
+}
+
+func TestMigrateKeepsOtherObjects(t *testing.T) {
+	// prepare
+	opts := migrateOptions{files: []string{"-"}, to: "0.24.0"}
+	stdin := strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n")
+
+	// test
+	results, err := migrateFiles(opts, stdin)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].changes)
+	assert.Equal(t, "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n", string(results[0].content))
+}
+
+func TestMigrateResourcesFromVersion(t *testing.T) {
+	// prepare
+	opts := migrateOptions{files: []string{"testdata/old.yaml"}, from: "0.20.0", to: "0.24.0"}
+
+	// test
+	results, err := migrateFiles(opts, nil)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Len(t, results[0].changes, 1)
+	assert.Contains(t, string(results[0].content), "reconnection_delay: 10s")
+}
+
+func TestMigrateConfig(t *testing.T) {
+	// prepare
+	opts := migrateOptions{files: []string{"-"}, from: "0.20.0", to: "0.24.0", config: true}
+	stdin := strings.NewReader("extensions:\n  health_check:\n    port: 13133\n")
+
+	// test
+	results, err := migrateFiles(opts, stdin)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "extensions:\n  health_check:\n    endpoint: 0.0.0.0:13133\n", string(results[0].content))
+	assert.Len(t, results[0].changes, 1)
+}
+
+func TestMigrateConfigRequiresSourceVersion(t *testing.T) {
+	// test
+	_, err := migrateFiles(migrateOptions{files: []string{"-"}, to: "0.24.0", config: true}, strings.NewReader(""))
+
+	// verify
+	assert.Error(t, err)
+}
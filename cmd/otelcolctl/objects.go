@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// readObjects reads all the objects from the given YAML or JSON files, which might contain multiple documents and
+// lists. The file "-" stands for the standard input.
+func readObjects(files []string, stdin io.Reader) ([]unstructured.Unstructured, error) {
+	objects := []unstructured.Unstructured{}
+	for _, file := range files {
+		var (
+			read []unstructured.Unstructured
+			err  error
+		)
+		if file == "-" {
+			read, err = decodeObjects(stdin)
+		} else {
+			read, err = readFile(file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		objects = append(objects, read...)
+	}
+
+	return objects, nil
+}
+
+func readFile(file string) ([]unstructured.Unstructured, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return decodeObjects(f)
+}
+
+func decodeObjects(r io.Reader) ([]unstructured.Unstructured, error) {
+	objects := []unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		obj := unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// empty documents
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		if !obj.IsList() {
+			objects = append(objects, obj)
+			continue
+		}
+
+		err = obj.EachListItem(func(item runtime.Object) error {
+			objects = append(objects, *item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// toUnstructured converts the object into its generic form, without the fields that are only set by the cluster.
+func toUnstructured(obj client.Object) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s: %w", obj.GetName(), err)
+	}
+
+	// the status is never set by the builders
+	delete(u, "status")
+	removeNilTimestamps(u)
+
+	return u, nil
+}
+
+func removeNilTimestamps(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ts, ok := v["creationTimestamp"]; ok && ts == nil {
+			delete(v, "creationTimestamp")
+		}
+		for _, child := range v {
+			removeNilTimestamps(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			removeNilTimestamps(child)
+		}
+	}
+}
+
+// printObjects writes the objects in the given format: "yaml" for YAML documents, "json" for a JSON list.
+func printObjects(out io.Writer, objects []map[string]interface{}, format string) error {
+	switch format {
+	case "yaml":
+		for _, obj := range objects {
+			b, err := sigsyaml.Marshal(obj)
+			if err != nil {
+				return fmt.Errorf("failed to marshal to YAML: %w", err)
+			}
+			fmt.Fprintf(out, "---\n%s", b)
+		}
+	case "json":
+		items := make([]interface{}, len(objects))
+		for i := range objects {
+			items[i] = objects[i]
+		}
+		list := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}
+		var b bytes.Buffer
+		encoder := json.NewEncoder(&b)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(list); err != nil {
+			return fmt.Errorf("failed to marshal to JSON: %w", err)
+		}
+		if _, err := b.WriteTo(out); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q, expected yaml or json", format)
+	}
+
+	return nil
+}
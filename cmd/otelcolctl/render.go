@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/distribution"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/reconcile"
+)
+
+// errNoInstances is returned when the given files have no OpenTelemetryCollector resources.
+var errNoInstances = errors.New("no OpenTelemetryCollector resources found in the given files")
+
+type renderOptions struct {
+	files          []string
+	output         string
+	diff           string
+	namespace      string
+	collectorImage string
+	distributions  string
+}
+
+// render prints the objects that the operator would create for the OpenTelemetryCollector resources from the files.
+func render(args []string, out io.Writer) error {
+	opts := renderOptions{}
+	fs := pflag.NewFlagSet("render", pflag.ContinueOnError)
+	fs.StringSliceVarP(&opts.files, "filename", "f", nil, "The files with the OpenTelemetryCollector resources, or - for the standard input")
+	fs.StringVarP(&opts.output, "output", "o", "yaml", "The output format, yaml or json")
+	fs.StringVar(&opts.diff, "diff", "", "A dump of the live objects, like the output of 'kubectl get -o yaml', to compare the rendered objects with")
+	fs.StringVarP(&opts.namespace, "namespace", "n", "default", "The namespace for the resources that don't specify one")
+	fs.StringVar(&opts.collectorImage, "otelcol-image", "", "The default image to use for OpenTelemetry Collector when not specified in the individual custom resource (CR)")
+	fs.StringVar(&opts.distributions, "collector-distributions", "", "The path to a file with additional OpenTelemetry Collector distributions, along with the components they include")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(opts.files) == 0 {
+		return errors.New("at least one file has to be specified with --filename")
+	}
+
+	objects, err := renderFiles(opts, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.diff) > 0 {
+		live, err := readObjects([]string{opts.diff}, os.Stdin)
+		if err != nil {
+			return err
+		}
+		return printDiff(out, objects, live)
+	}
+
+	return printObjects(out, objects, opts.output)
+}
+
+// renderFiles builds the objects for all the OpenTelemetryCollector resources from the files.
+func renderFiles(opts renderOptions, stdin io.Reader) ([]map[string]interface{}, error) {
+	read, err := readObjects(opts.files, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgOpts := []config.Option{config.WithLogger(ctrl.Log.WithName("config"))}
+	if len(opts.collectorImage) > 0 {
+		cfgOpts = append(cfgOpts, config.WithCollectorImage(opts.collectorImage))
+	}
+	cfg := config.New(cfgOpts...)
+
+	distributions, err := distribution.Load(opts.distributions)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := []map[string]interface{}{}
+	found := false
+	for _, u := range read {
+		if u.GroupVersionKind() != v1alpha1.GroupVersion.WithKind("OpenTelemetryCollector") {
+			continue
+		}
+		found = true
+
+		instance, err := toInstance(u, opts.namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		objects, err := renderInstance(cfg, distributions, instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s/%s: %w", instance.Namespace, instance.Name, err)
+		}
+		for _, obj := range objects {
+			m, err := toUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, m)
+		}
+	}
+
+	if !found {
+		return nil, errNoInstances
+	}
+
+	return rendered, nil
+}
+
+func toInstance(u unstructured.Unstructured, namespace string) (v1alpha1.OpenTelemetryCollector, error) {
+	instance := v1alpha1.OpenTelemetryCollector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &instance); err != nil {
+		return instance, fmt.Errorf("failed to parse the OpenTelemetryCollector %s: %w", u.GetName(), err)
+	}
+
+	if len(instance.Namespace) == 0 {
+		instance.Namespace = namespace
+	}
+
+	return instance, nil
+}
+
+// renderInstance runs the same defaulting and validation as the webhooks, and then builds the objects the same way as
+// the reconciliation tasks.
+func renderInstance(cfg config.Config, distributions *distribution.Catalog, instance v1alpha1.OpenTelemetryCollector) ([]client.Object, error) {
+	instance.Default()
+	if len(instance.Spec.Distribution) == 0 {
+		instance.Spec.Distribution = distributions.ForImage(collector.Image(cfg, instance))
+	}
+	if err := instance.ValidateCreate(); err != nil {
+		return nil, fmt.Errorf("invalid resource: %w", err)
+	}
+	if err := distributions.Validate(&instance); err != nil {
+		return nil, fmt.Errorf("invalid resource: %w", err)
+	}
+
+	params := reconcile.Params{
+		Config:   cfg,
+		Instance: instance,
+		Log:      ctrl.Log.WithName("render").WithValues("opentelemetrycollector", instance.Name),
+		Scheme:   scheme,
+	}
+
+	return reconcile.Desired(context.Background(), params)
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRenderFiles(t *testing.T) {
+	// test
+	objects, err := renderFiles(renderOptions{files: []string{"testdata/simplest.yaml"}, namespace: "observability"}, nil)
+
+	// verify
+	require.NoError(t, err)
+	kinds := []string{}
+	for _, obj := range objects {
+		u := unstructured.Unstructured{Object: obj}
+		kinds = append(kinds, u.GetKind())
+		assert.Equal(t, "observability", u.GetNamespace())
+		assert.NotContains(t, u.Object, "status")
+	}
+	assert.Contains(t, kinds, "ConfigMap")
+	assert.Contains(t, kinds, "ServiceAccount")
+	assert.Contains(t, kinds, "Service")
+	assert.Contains(t, kinds, "Deployment") // the default mode
+}
+
+func TestRenderFromStandardInput(t *testing.T) {
+	// prepare
+	stdin := strings.NewReader(`---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: opentelemetry.io/v1alpha1
+  kind: OpenTelemetryCollector
+  metadata:
+    name: from-list
+  spec:
+    mode: sidecar
+`)
+
+	// test
+	objects, err := renderFiles(renderOptions{files: []string{"-"}, namespace: "default"}, stdin)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "ConfigMap", objects[0]["kind"])
+}
+
+func TestRenderRunsValidation(t *testing.T) {
+	// test
+	_, err := renderFiles(renderOptions{files: []string{"testdata/invalid.yaml"}}, nil)
+
+	// verify
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "observability/invalid")
+	assert.Contains(t, err.Error(), "replicas")
+}
+
+func TestRenderValidatesTheComponents(t *testing.T) {
+	// prepare
+	stdin := strings.NewReader(`apiVersion: opentelemetry.io/v1alpha1
+kind: OpenTelemetryCollector
+metadata:
+  name: contrib-receiver
+spec:
+  config: |
+    receivers:
+      filelog:
+    exporters:
+      logging:
+`)
+
+	// test
+	_, err := renderFiles(renderOptions{files: []string{"-"}, namespace: "default"}, stdin)
+
+	// verify
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `receiver "filelog"`)
+}
+
+func TestRenderWithoutInstances(t *testing.T) {
+	// test
+	_, err := renderFiles(renderOptions{files: []string{"-"}}, strings.NewReader("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n"))
+
+	// verify
+	assert.Equal(t, errNoInstances, err)
+}
+
+func TestPrintObjects(t *testing.T) {
+	for _, tt := range []struct {
+		format   string
+		expected string
+	}{
+		{"yaml", "---\napiVersion: v1\nkind: ConfigMap\n"},
+		{"json", "{\n  \"apiVersion\": \"v1\",\n  \"items\": [\n    {\n      \"apiVersion\": \"v1\",\n      \"kind\": \"ConfigMap\"\n    }\n  ],\n  \"kind\": \"List\"\n}\n"},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			// prepare
+			out := &bytes.Buffer{}
+
+			// test
+			err := printObjects(out, []map[string]interface{}{{"apiVersion": "v1", "kind": "ConfigMap"}}, tt.format)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out.String())
+		})
+	}
+
+	assert.Error(t, printObjects(&bytes.Buffer{}, nil, "xml"))
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The possible outcomes of a reconciliation task.
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+var (
+	taskDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "opentelemetry_operator_reconcile_task_duration_seconds",
+			Help: "Time spent running each reconciliation task, by task and outcome",
+		},
+		[]string{"task", "outcome"},
+	)
+
+	taskTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "opentelemetry_operator_reconcile_task_total",
+			Help: "Number of reconciliation task runs, by task and outcome",
+		},
+		[]string{"task", "outcome"},
+	)
+)
+
+func init() {
+	// the metrics are exposed by the manager's metrics endpoint
+	metrics.Registry.MustRegister(taskDuration, taskTotal)
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+)
+
+func TestInstancesInSelectedNamespace(t *testing.T) {
+	// prepare
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "team-a"}},
+		&v1alpha1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "team-b"}},
+	).Build()
+
+	cfg := config.New()
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"opentelemetry": "enabled"}},
+	})
+	reconciler := NewReconciler(Params{Client: cl, Scheme: scheme, Config: cfg})
+
+	// test
+	selected := reconciler.instancesInNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"opentelemetry": "enabled"}}})
+	other := reconciler.instancesInNamespace(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}})
+
+	// verify
+	assert.Equal(t, []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: "team-a", Name: "first"}}}, selected)
+	assert.Empty(t, other)
+}
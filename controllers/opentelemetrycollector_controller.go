@@ -17,22 +17,39 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/reconcile"
 )
 
+// routeGVK is the kind of the OpenShift routes exposing the receivers of the instances.
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
 // OpenTelemetryCollectorReconciler reconciles a OpenTelemetryCollector object.
 type OpenTelemetryCollectorReconciler struct {
 	client.Client
@@ -41,6 +58,7 @@ type OpenTelemetryCollectorReconciler struct {
 	config   config.Config
 	tasks    []Task
 	recorder record.EventRecorder
+	refresh  *refreshSource
 }
 
 // Task represents a reconciliation task to be executed by the reconciler.
@@ -79,6 +97,16 @@ func NewReconciler(p Params) *OpenTelemetryCollectorReconciler {
 				reconcile.Services,
 				true,
 			},
+			{
+				"routes",
+				reconcile.Routes,
+				true,
+			},
+			{
+				"tls certificates",
+				reconcile.TLSCertificates,
+				true,
+			},
 			{
 				"deployments",
 				reconcile.Deployments,
@@ -109,6 +137,7 @@ func NewReconciler(p Params) *OpenTelemetryCollectorReconciler {
 		config:   p.Config,
 		tasks:    p.Tasks,
 		recorder: p.Recorder,
+		refresh:  &refreshSource{},
 	}
 }
 
@@ -135,6 +164,16 @@ func (r *OpenTelemetryCollectorReconciler) Reconcile(_ context.Context, req ctrl
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	inScope, err := r.config.NamespaceInScope(ctx, r.Client, instance.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !inScope {
+		// the objects of the instance are left as they are, like when the reconciliation is paused
+		log.V(1).Info("skipping the instance, as its namespace isn't selected by the operator's namespace selector")
+		return ctrl.Result{}, nil
+	}
+
 	params := reconcile.Params{
 		Config:   r.config,
 		Client:   r.Client,
@@ -144,30 +183,200 @@ func (r *OpenTelemetryCollectorReconciler) Reconcile(_ context.Context, req ctrl
 		Recorder: r.recorder,
 	}
 
-	if err := r.RunTasks(ctx, params); err != nil {
+	if instance.Annotations[v1alpha1.AnnotationPaused] == "true" {
+		// the tasks are skipped, so that manual changes to the managed objects aren't reverted
+		if err := r.reportPaused(ctx, params); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	resumed := meta.IsStatusConditionTrue(instance.Status.Conditions, v1alpha1.ConditionPaused)
+	if resumed {
+		r.event(&instance, "Normal", "Resumed", "The reconciliation has been resumed")
+	}
+
+	failures, err := r.runTasks(ctx, params)
+
+	// the status is reported even when a task bailed out, so that the failure is visible on the instance
+	statusErr := r.patchStatus(ctx, req.NamespacedName, func(instance *v1alpha1.OpenTelemetryCollector) {
+		setDegradedCondition(instance, failures)
+		setProbesCondition(instance)
+		if resumed {
+			meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+				Type:               v1alpha1.ConditionPaused,
+				Status:             metav1.ConditionFalse,
+				Reason:             v1alpha1.ReasonResumed,
+				Message:            "The reconciliation has been resumed",
+				ObservedGeneration: instance.Generation,
+			})
+			instance.Status.Drift = nil
+		}
+	})
+	if statusErr != nil {
+		log.Error(statusErr, "failed to update the status of the OpenTelemetryCollector")
+	}
+
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// the certificates issued by the operator are only renewed when the instance is reconciled
+	result := ctrl.Result{}
+	renewal, scheduled, err := reconcile.TLSRenewal(ctx, params)
+	switch {
+	case err != nil:
+		log.Error(err, "failed to determine when the certificates have to be renewed")
+	case scheduled:
+		log.V(2).Info("scheduled the renewal of the certificates", "after", renewal)
+		result.RequeueAfter = renewal
+	}
+
+	return result, nil
 }
 
 // RunTasks runs all the tasks associated with this reconciler.
 func (r *OpenTelemetryCollectorReconciler) RunTasks(ctx context.Context, params reconcile.Params) error {
+	_, err := r.runTasks(ctx, params)
+	return err
+}
+
+// taskFailure holds the error returned by a failed task.
+type taskFailure struct {
+	task string
+	err  error
+}
+
+// runTasks runs the tasks, recording their outcome as metrics and each failure as an event on the instance.
+// The returned error is only set when a task that should bail on errors has failed.
+func (r *OpenTelemetryCollectorReconciler) runTasks(ctx context.Context, params reconcile.Params) ([]taskFailure, error) {
+	var failures []taskFailure
 	for _, task := range r.tasks {
-		if err := task.Do(ctx, params); err != nil {
+		start := time.Now()
+		err := task.Do(ctx, params)
+
+		outcome := outcomeSuccess
+		if err != nil {
+			outcome = outcomeError
+		}
+		taskDuration.WithLabelValues(task.Name, outcome).Observe(time.Since(start).Seconds())
+		taskTotal.WithLabelValues(task.Name, outcome).Inc()
+
+		if err != nil {
 			r.log.Error(err, fmt.Sprintf("failed to reconcile %s", task.Name))
+			r.event(&params.Instance, "Warning", "TaskFailed", fmt.Sprintf("Failed to reconcile %s: %s", task.Name, err))
+			failures = append(failures, taskFailure{task: task.Name, err: err})
 			if task.BailOnError {
-				return err
+				return failures, err
 			}
 		}
 	}
 
+	return failures, nil
+}
+
+// event records an event on the given instance, unless the reconciler was built without a recorder.
+func (r *OpenTelemetryCollectorReconciler) event(instance *v1alpha1.OpenTelemetryCollector, eventType, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Event(instance, eventType, reason, message)
+}
+
+// reportPaused records that the reconciliation of the instance is paused, along with the differences between the
+// desired objects and the ones in the cluster.
+func (r *OpenTelemetryCollectorReconciler) reportPaused(ctx context.Context, params reconcile.Params) error {
+	drift, err := reconcile.Drift(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to determine the drift of the paused instance: %w", err)
+	}
+
+	if !meta.IsStatusConditionTrue(params.Instance.Status.Conditions, v1alpha1.ConditionPaused) {
+		r.event(&params.Instance, "Normal", "Paused", fmt.Sprintf("The reconciliation has been paused via the %s annotation", v1alpha1.AnnotationPaused))
+	}
+
+	nsn := types.NamespacedName{Namespace: params.Instance.Namespace, Name: params.Instance.Name}
+	return r.patchStatus(ctx, nsn, func(instance *v1alpha1.OpenTelemetryCollector) {
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionPaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             v1alpha1.ReasonPaused,
+			Message:            fmt.Sprintf("The reconciliation is paused, remove the %s annotation to resume it", v1alpha1.AnnotationPaused),
+			ObservedGeneration: instance.Generation,
+		})
+		instance.Status.Drift = drift
+	})
+}
+
+// patchStatus applies the given changes to the latest version of the instance, sending the status to the API server
+// only when it has changed. The instance is retrieved again, as the tasks might have changed it.
+func (r *OpenTelemetryCollectorReconciler) patchStatus(ctx context.Context, nsn types.NamespacedName, mutate func(*v1alpha1.OpenTelemetryCollector)) error {
+	var instance v1alpha1.OpenTelemetryCollector
+	if err := r.Get(ctx, nsn, &instance); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	changed := instance.DeepCopy()
+	mutate(changed)
+	if apiequality.Semantic.DeepEqual(instance.Status, changed.Status) {
+		return nil
+	}
+
+	if err := r.Status().Patch(ctx, changed, client.MergeFrom(&instance)); err != nil {
+		return fmt.Errorf("failed to apply status changes to the OpenTelemetry CR: %w", err)
+	}
+
 	return nil
 }
 
-// SetupWithManager tells the manager what our controller is interested in.
+// setDegradedCondition sets the Degraded condition of the instance based on the failed tasks.
+func setDegradedCondition(instance *v1alpha1.OpenTelemetryCollector, failures []taskFailure) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             v1alpha1.ReasonTasksSucceeded,
+		Message:            "All the reconciliation tasks have succeeded",
+		ObservedGeneration: instance.Generation,
+	}
+	if len(failures) > 0 {
+		msgs := make([]string, len(failures))
+		for i, failure := range failures {
+			msgs[i] = fmt.Sprintf("failed to reconcile %s: %s", failure.task, failure.err)
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = v1alpha1.ReasonTaskFailed
+		condition.Message = strings.Join(msgs, "; ")
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+}
+
+// setProbesCondition sets the ProbesEnabled condition of the instance, based on its mode and the health_check extension
+// of its configuration.
+func setProbesCondition(instance *v1alpha1.OpenTelemetryCollector) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.ConditionProbesEnabled,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.ReasonHealthCheckEnabled,
+		Message:            "The liveness and readiness probes check the health_check extension",
+		ObservedGeneration: instance.Generation,
+	}
+	if _, _, err := collector.Probes(*instance); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.ReasonHealthCheckMissing
+		condition.Message = fmt.Sprintf("The liveness and readiness probes are disabled: %s", err)
+		if errors.Is(err, collector.ErrSidecarProbes) {
+			condition.Reason = v1alpha1.ReasonSidecarMode
+		}
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+}
+
+// SetupWithManager tells the manager what our controller is interested in. The routes are only watched when the
+// capabilities of the cluster include OpenShift's route API, as the watch would fail otherwise.
 func (r *OpenTelemetryCollectorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.OpenTelemetryCollector{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -175,5 +384,90 @@ func (r *OpenTelemetryCollectorReconciler) SetupWithManager(mgr ctrl.Manager) er
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
 		Owns(&appsv1.StatefulSet{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(secretOwner), builder.WithPredicates(predicate.NewPredicateFuncs(managedByOperator))).
+		Watches(r.refresh, &handler.EnqueueRequestForObject{}).
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.instancesInNamespace), builder.WithPredicates(predicate.LabelChangedPredicate{}))
+
+	if r.config.Capabilities().OpenShiftRoutes {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(routeGVK)
+		bldr = bldr.Owns(route)
+	}
+
+	return bldr.Complete(r)
+}
+
+// instancesInNamespace maps a namespace to the instances it contains, so that they are reconciled once the namespace
+// is selected by the operator's namespace selector.
+func (r *OpenTelemetryCollectorReconciler) instancesInNamespace(obj client.Object) []ctrl.Request {
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok || !r.config.NamespaceMatches(*ns) {
+		return nil
+	}
+
+	var instances v1alpha1.OpenTelemetryCollectorList
+	if err := r.List(context.Background(), &instances, client.InNamespace(ns.Name)); err != nil {
+		r.log.Error(err, "failed to list the instances of the namespace", "namespace", ns.Name)
+		return nil
+	}
+
+	return requestsFor(instances)
+}
+
+// requestsFor returns the requests for reconciling the given instances.
+func requestsFor(instances v1alpha1.OpenTelemetryCollectorList) []ctrl.Request {
+	requests := make([]ctrl.Request, len(instances.Items))
+	for i, instance := range instances.Items {
+		requests[i] = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}}
+	}
+	return requests
+}
+
+// Refresh enqueues all the instances for reconciliation, like when the capabilities of the cluster have changed.
+// Nothing is enqueued while the controller isn't running, as all the instances are reconciled once it starts.
+func (r *OpenTelemetryCollectorReconciler) Refresh(ctx context.Context) error {
+	if !r.refresh.started() {
+		return nil
+	}
+
+	var instances v1alpha1.OpenTelemetryCollectorList
+	if err := r.List(ctx, &instances); err != nil {
+		return fmt.Errorf("failed to list the instances to refresh: %w", err)
+	}
+
+	r.refresh.enqueue(requestsFor(instances))
+
+	return nil
+}
+
+// managedByOperator filters out the objects that weren't created for an instance, like the secrets of the
+// applications: all the secrets of the instances, including the ones issued by cert-manager, carry this label.
+func managedByOperator(obj client.Object) bool {
+	return obj.GetLabels()["app.kubernetes.io/managed-by"] == "opentelemetry-operator"
+}
+
+// secretOwner maps a secret to the instance it belongs to. The secrets created by the operator are controlled by their
+// instance, while the secrets issued by cert-manager for an instance aren't owned by it and are mapped based on their
+// labels. A single watch handles both, so that each event on a secret is only enqueued once.
+func secretOwner(obj client.Object) []ctrl.Request {
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil || gv.Group != v1alpha1.GroupVersion.Group || owner.Kind != "OpenTelemetryCollector" {
+			return nil
+		}
+		return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}}}
+	}
+
+	labels := obj.GetLabels()
+	if labels["app.kubernetes.io/managed-by"] != "opentelemetry-operator" {
+		return nil
+	}
+
+	// the namespace can't contain dots, but the name of the instance can
+	parts := strings.SplitN(labels["app.kubernetes.io/instance"], ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: parts[0], Name: parts[1]}}}
 }
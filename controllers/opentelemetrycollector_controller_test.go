@@ -19,18 +19,22 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/kubectl/pkg/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8sconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	k8sreconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
@@ -244,6 +248,40 @@ func TestContinueOnRecoverableFailure(t *testing.T) {
 	assert.True(t, taskCalled)
 }
 
+func TestReportTaskFailures(t *testing.T) {
+	// prepare
+	recorder := record.NewFakeRecorder(10)
+	reconciler := controllers.NewReconciler(controllers.Params{
+		Log:      logger,
+		Recorder: recorder,
+		Tasks: []controllers.Task{
+			{
+				Name: "should-report-failure",
+				Do: func(context.Context, reconcile.Params) error {
+					return errors.New("should fail!")
+				},
+				BailOnError: false,
+			},
+			{
+				Name: "should-report-success",
+				Do: func(context.Context, reconcile.Params) error {
+					return nil
+				},
+			},
+		},
+	})
+
+	// test
+	err := reconciler.RunTasks(context.Background(), reconcile.Params{})
+
+	// verify
+	assert.NoError(t, err)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning TaskFailed Failed to reconcile should-report-failure: should fail!", <-recorder.Events)
+	assert.Equal(t, 1, taskRuns(t, "should-report-failure", "error"))
+	assert.Equal(t, 1, taskRuns(t, "should-report-success", "success"))
+}
+
 func TestBreakOnUnrecoverableError(t *testing.T) {
 	// prepare
 	cfg := config.New()
@@ -251,10 +289,11 @@ func TestBreakOnUnrecoverableError(t *testing.T) {
 	expectedErr := errors.New("should fail!")
 	nsn := types.NamespacedName{Name: "my-instance", Namespace: "default"}
 	reconciler := controllers.NewReconciler(controllers.Params{
-		Client: k8sClient,
-		Log:    logger,
-		Scheme: scheme.Scheme,
-		Config: cfg,
+		Client:   k8sClient,
+		Log:      logger,
+		Scheme:   scheme.Scheme,
+		Config:   cfg,
+		Recorder: record.NewFakeRecorder(10),
 		Tasks: []controllers.Task{
 			{
 				Name: "should-fail",
@@ -292,10 +331,130 @@ func TestBreakOnUnrecoverableError(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 	assert.True(t, taskCalled)
 
+	actual := &v1alpha1.OpenTelemetryCollector{}
+	require.NoError(t, k8sClient.Get(context.Background(), nsn, actual))
+	degraded := meta.FindStatusCondition(actual.Status.Conditions, v1alpha1.ConditionDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, v1alpha1.ReasonTaskFailed, degraded.Reason)
+	assert.Contains(t, degraded.Message, "should-fail")
+
+	// cleanup
+	assert.NoError(t, k8sClient.Delete(context.Background(), created))
+}
+
+func TestPauseReconciliation(t *testing.T) {
+	// prepare
+	taskCalled := false
+	recorder := record.NewFakeRecorder(10)
+	nsn := types.NamespacedName{Name: "my-paused-instance", Namespace: "default"}
+	reconciler := controllers.NewReconciler(controllers.Params{
+		Client:   k8sClient,
+		Log:      logger,
+		Scheme:   testScheme,
+		Config:   config.New(),
+		Recorder: recorder,
+		Tasks: []controllers.Task{
+			{
+				Name: "should-be-called-after-resuming",
+				Do: func(context.Context, reconcile.Params) error {
+					taskCalled = true
+					return nil
+				},
+			},
+		},
+	})
+	created := &v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nsn.Name,
+			Namespace:   nsn.Namespace,
+			Annotations: map[string]string{v1alpha1.AnnotationPaused: "true"},
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode: v1alpha1.ModeDeployment,
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), created))
+	req := k8sreconcile.Request{
+		NamespacedName: nsn,
+	}
+
+	// test
+	_, err := reconciler.Reconcile(context.Background(), req)
+
+	// verify
+	require.NoError(t, err)
+	assert.False(t, taskCalled)
+	assert.Equal(t, "Normal Paused The reconciliation has been paused via the opentelemetry.io/paused annotation", <-recorder.Events)
+
+	actual := &v1alpha1.OpenTelemetryCollector{}
+	require.NoError(t, k8sClient.Get(context.Background(), nsn, actual))
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, v1alpha1.ConditionPaused))
+	assert.Contains(t, actual.Status.Drift, "Deployment default/my-paused-instance-collector: missing, would be created")
+
+	// test
+	delete(actual.Annotations, v1alpha1.AnnotationPaused)
+	require.NoError(t, k8sClient.Update(context.Background(), actual))
+	_, err = reconciler.Reconcile(context.Background(), req)
+
+	// verify
+	require.NoError(t, err)
+	assert.True(t, taskCalled)
+	assert.Equal(t, "Normal Resumed The reconciliation has been resumed", <-recorder.Events)
+
+	require.NoError(t, k8sClient.Get(context.Background(), nsn, actual))
+	assert.True(t, meta.IsStatusConditionFalse(actual.Status.Conditions, v1alpha1.ConditionPaused))
+	assert.Empty(t, actual.Status.Drift)
+
 	// cleanup
 	assert.NoError(t, k8sClient.Delete(context.Background(), created))
 }
 
+func TestRequeueForCertificateRenewal(t *testing.T) {
+	// prepare
+	nsn := types.NamespacedName{Name: "my-tls-instance", Namespace: "default"}
+	reconciler := controllers.NewReconciler(controllers.Params{
+		Client:   k8sClient,
+		Log:      logger,
+		Scheme:   testScheme,
+		Config:   config.New(),
+		Recorder: record.NewFakeRecorder(10),
+		Tasks: []controllers.Task{
+			{
+				Name:        "tls certificates",
+				Do:          reconcile.TLSCertificates,
+				BailOnError: true,
+			},
+		},
+	})
+	created := &v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsn.Name,
+			Namespace: nsn.Namespace,
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode: v1alpha1.ModeDeployment,
+			TLS: &v1alpha1.TLSSpec{
+				Issuer:      v1alpha1.TLSIssuerOperator,
+				Duration:    &metav1.Duration{Duration: 24 * time.Hour},
+				RenewBefore: &metav1.Duration{Duration: 8 * time.Hour},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), created))
+	defer func() {
+		require.NoError(t, k8sClient.Delete(context.Background(), created))
+	}()
+
+	// test
+	result, err := reconciler.Reconcile(context.Background(), k8sreconcile.Request{NamespacedName: nsn})
+
+	// verify
+	require.NoError(t, err)
+	assert.LessOrEqual(t, int64(result.RequeueAfter), int64(16*time.Hour))
+	assert.Greater(t, int64(result.RequeueAfter), int64(15*time.Hour))
+}
+
 func TestSkipWhenInstanceDoesNotExist(t *testing.T) {
 	// prepare
 	cfg := config.New()
@@ -341,3 +500,26 @@ func TestRegisterWithManager(t *testing.T) {
 	// verify
 	assert.NoError(t, err)
 }
+
+// taskRuns returns the number of runs of the given task with the given outcome, as reported by the metrics.
+func taskRuns(t *testing.T, task, outcome string) int {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "opentelemetry_operator_reconcile_task_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["task"] == task && labels["outcome"] == outcome {
+				return int(metric.GetCounter().GetValue())
+			}
+		}
+	}
+
+	return 0
+}
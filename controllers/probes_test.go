@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestProbesCondition(t *testing.T) {
+	// prepare
+	instance := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "extensions:\n  health_check:\nservice:\n  extensions: [health_check]\n",
+		},
+	}
+
+	// test
+	setProbesCondition(&instance)
+
+	// verify
+	condition := meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionProbesEnabled)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1alpha1.ReasonHealthCheckEnabled, condition.Reason)
+
+	// test
+	instance.Spec.Config = "extensions:\n  health_check:\nservice:\n  extensions: []\n"
+	setProbesCondition(&instance)
+
+	// verify
+	condition = meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionProbesEnabled)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1alpha1.ReasonHealthCheckMissing, condition.Reason)
+	assert.Contains(t, condition.Message, "isn't enabled in service.extensions")
+
+	// test
+	instance.Spec.Mode = v1alpha1.ModeSidecar
+	instance.Spec.Config = "extensions:\n  health_check:\nservice:\n  extensions: [health_check]\n"
+	setProbesCondition(&instance)
+
+	// verify
+	condition = meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ConditionProbesEnabled)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1alpha1.ReasonSidecarMode, condition.Reason)
+}
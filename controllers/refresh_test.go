@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestRefreshEnqueuesAllInstances(t *testing.T) {
+	// prepare
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}},
+		&v1alpha1.OpenTelemetryCollector{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "observability"}},
+	).Build()
+	reconciler := NewReconciler(Params{Client: cl, Scheme: scheme})
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	// test
+	require.NoError(t, reconciler.Refresh(context.Background()))
+	notStarted := queue.Len()
+
+	require.NoError(t, reconciler.refresh.Start(context.Background(), nil, queue))
+	require.NoError(t, reconciler.Refresh(context.Background()))
+
+	// verify
+	assert.Equal(t, 0, notStarted)
+	require.Equal(t, 2, queue.Len())
+	first, _ := queue.Get()
+	second, _ := queue.Get()
+	assert.ElementsMatch(t, []interface{}{
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "first"}},
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "observability", Name: "second"}},
+	}, []interface{}{first, second})
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestSecretOwner(t *testing.T) {
+	isController := true
+	instance := []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: "observability", Name: "my.instance"}}}
+
+	for _, tt := range []struct {
+		desc     string
+		meta     metav1.ObjectMeta
+		expected []ctrl.Request
+	}{
+		{
+			"controlled by an instance",
+			metav1.ObjectMeta{
+				Namespace: "observability",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       "OpenTelemetryCollector",
+					Name:       "my.instance",
+					Controller: &isController,
+				}},
+			},
+			instance,
+		},
+		{
+			"controlled by another kind",
+			metav1.ObjectMeta{
+				Namespace: "observability",
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "opentelemetry-operator",
+					"app.kubernetes.io/instance":   "observability.my.instance",
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "cert-manager.io/v1",
+					Kind:       "Certificate",
+					Name:       "my-certificate",
+					Controller: &isController,
+				}},
+			},
+			nil,
+		},
+		{
+			"issued for an instance",
+			metav1.ObjectMeta{
+				Namespace: "observability",
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "opentelemetry-operator",
+					"app.kubernetes.io/instance":   "observability.my.instance",
+				},
+			},
+			instance,
+		},
+		{
+			"not managed by the operator",
+			metav1.ObjectMeta{
+				Namespace: "observability",
+				Labels:    map[string]string{"app.kubernetes.io/instance": "observability.my.instance"},
+			},
+			nil,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			requests := secretOwner(&corev1.Secret{ObjectMeta: tt.meta})
+
+			// verify
+			assert.Equal(t, tt.expected, requests)
+		})
+	}
+}
+
+func TestManagedByOperator(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		labels   map[string]string
+		expected bool
+	}{
+		{
+			"managed by the operator",
+			map[string]string{"app.kubernetes.io/managed-by": "opentelemetry-operator"},
+			true,
+		},
+		{
+			"managed by another tool",
+			map[string]string{"app.kubernetes.io/managed-by": "helm"},
+			false,
+		},
+		{
+			"without labels",
+			nil,
+			false,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			managed := managedByOperator(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}})
+
+			// verify
+			assert.Equal(t, tt.expected, managed)
+		})
+	}
+}
@@ -5,14 +5,21 @@ go 1.16
 require (
 	github.com/Masterminds/semver/v3 v3.1.1
 	github.com/go-logr/logr v0.4.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/prometheus/client_golang v1.9.0
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0
+	go.uber.org/zap v1.16.0
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 	k8s.io/api v0.21.2
 	k8s.io/apimachinery v0.21.2
 	k8s.io/client-go v0.21.2
+	k8s.io/component-base v0.21.2
 	k8s.io/kubectl v0.21.2
 	sigs.k8s.io/controller-runtime v0.9.0-beta.5
+	sigs.k8s.io/structured-merge-diff/v4 v4.1.0
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certificate generates the certificate authorities and serving certificates managed by the operator.
+package certificate
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// clockSkew is subtracted from the start of the validity of the certificates, so that they are accepted right away
+// by the nodes with a clock slightly behind the operator's.
+const clockSkew = 5 * time.Minute
+
+// ErrInvalidPEM is returned when a certificate or a key can't be decoded.
+var ErrInvalidPEM = errors.New("invalid PEM data")
+
+// KeyPair holds a PEM-encoded certificate along with its private key.
+type KeyPair struct {
+	Certificate []byte
+	Key         []byte
+}
+
+// NewCA generates a self-signed certificate authority with the given common name, valid for the given duration.
+func NewCA(commonName string, validity time.Duration) (KeyPair, error) {
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to generate the key: %w", err)
+	}
+
+	return encode(template, template, key, key)
+}
+
+// NewServing generates a serving certificate for the given DNS names, signed by the given certificate authority and
+// valid for the given duration. The first DNS name is used as the common name.
+func NewServing(ca KeyPair, dnsNames []string, validity time.Duration) (KeyPair, error) {
+	if len(dnsNames) == 0 {
+		return KeyPair{}, errors.New("at least one DNS name is required for a serving certificate")
+	}
+
+	caCert, err := Parse(ca.Certificate)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to parse the certificate authority: %w", err)
+	}
+	caKey, err := parseKey(ca.Key)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to parse the key of the certificate authority: %w", err)
+	}
+
+	template, err := newTemplate(dnsNames[0], validity)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.DNSNames = dnsNames
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to generate the key: %w", err)
+	}
+
+	return encode(template, caCert, key, caKey)
+}
+
+// Parse decodes the first PEM-encoded certificate from the given data.
+func Parse(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidPEM
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// NeedsRenewal returns whether the given PEM-encoded certificate should be replaced: when it can't be parsed, when it
+// expires within the given period, when it doesn't cover all the given DNS names, or when it isn't signed by the given
+// PEM-encoded certificate authority. The DNS names and the authority are only checked when set.
+func NeedsRenewal(data []byte, renewBefore time.Duration, dnsNames []string, ca []byte) bool {
+	cert, err := Parse(data)
+	if err != nil {
+		return true
+	}
+
+	if time.Now().Add(renewBefore).After(cert.NotAfter) {
+		return true
+	}
+
+	if len(dnsNames) > 0 && !sameNames(cert.DNSNames, dnsNames) {
+		return true
+	}
+
+	if len(ca) > 0 {
+		caCert, err := Parse(ca)
+		if err != nil {
+			return true
+		}
+		if err := cert.CheckSignatureFrom(caCert); err != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the given PEM data, hex-encoded.
+func Fingerprint(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(bytes.TrimSpace(data)))
+}
+
+// Unexpired returns the PEM-encoded certificates of the given bundle that haven't expired yet, in the same order. The
+// blocks that aren't certificates or can't be parsed are dropped.
+func Unexpired(bundle []byte) []byte {
+	var unexpired []byte
+	now := time.Now()
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return unexpired
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(cert.NotAfter) {
+			continue
+		}
+		unexpired = append(unexpired, pem.EncodeToMemory(block)...)
+	}
+}
+
+func newTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func encode(template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) (KeyPair, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to create the certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return KeyPair{}, fmt.Errorf("failed to encode the key: %w", err)
+	}
+
+	return KeyPair{
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+func parseKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, ErrInvalidPEM
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func sameNames(actual, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	a := append([]string{}, actual...)
+	e := append([]string{}, expected...)
+	sort.Strings(a)
+	sort.Strings(e)
+	for i := range a {
+		if a[i] != e[i] {
+			return false
+		}
+	}
+	return true
+}
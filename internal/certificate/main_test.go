@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServingCertificateSignedByCA(t *testing.T) {
+	// prepare
+	ca, err := NewCA("my-ca", 24*time.Hour)
+	require.NoError(t, err)
+
+	// test
+	serving, err := NewServing(ca, []string{"my-service.default.svc", "my-service"}, time.Hour)
+
+	// verify
+	require.NoError(t, err)
+	cert, err := Parse(serving.Certificate)
+	require.NoError(t, err)
+	assert.Equal(t, "my-service.default.svc", cert.Subject.CommonName)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(ca.Certificate))
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName: "my-service",
+		Roots:   roots,
+	})
+	assert.NoError(t, err)
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	// prepare
+	ca, err := NewCA("my-ca", 24*time.Hour)
+	require.NoError(t, err)
+	otherCA, err := NewCA("other-ca", 24*time.Hour)
+	require.NoError(t, err)
+	serving, err := NewServing(ca, []string{"a", "b"}, 10*time.Hour)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		desc        string
+		data        []byte
+		renewBefore time.Duration
+		dnsNames    []string
+		ca          []byte
+		expected    bool
+	}{
+		{"valid", serving.Certificate, time.Hour, []string{"b", "a"}, ca.Certificate, false},
+		{"about to expire", serving.Certificate, 11 * time.Hour, nil, nil, true},
+		{"different names", serving.Certificate, time.Hour, []string{"a", "c"}, nil, true},
+		{"other authority", serving.Certificate, time.Hour, nil, otherCA.Certificate, true},
+		{"invalid", []byte("not a certificate"), time.Hour, nil, nil, true},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			renew := NeedsRenewal(tt.data, tt.renewBefore, tt.dnsNames, tt.ca)
+
+			// verify
+			assert.Equal(t, tt.expected, renew)
+		})
+	}
+}
+
+func TestServingRequiresDNSNames(t *testing.T) {
+	// prepare
+	ca, err := NewCA("my-ca", time.Hour)
+	require.NoError(t, err)
+
+	// test
+	_, err = NewServing(ca, nil, time.Hour)
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestUnexpired(t *testing.T) {
+	// prepare
+	current, err := NewCA("current-ca", 24*time.Hour)
+	require.NoError(t, err)
+	previous, err := NewCA("previous-ca", time.Hour)
+	require.NoError(t, err)
+	expired, err := NewCA("expired-ca", -time.Minute)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		desc     string
+		bundle   []byte
+		expected []byte
+	}{
+		{"single", current.Certificate, current.Certificate},
+		{"rotated", concat(current.Certificate, previous.Certificate), concat(current.Certificate, previous.Certificate)},
+		{"expired", concat(current.Certificate, expired.Certificate), current.Certificate},
+		{"with other blocks", concat(current.Certificate, current.Key), current.Certificate},
+		{"empty", nil, nil},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			unexpired := Unexpired(tt.bundle)
+
+			// verify
+			assert.Equal(t, tt.expected, unexpired)
+		})
+	}
+}
+
+func concat(certificates ...[]byte) []byte {
+	var bundle []byte
+	for _, c := range certificates {
+		bundle = append(bundle, c...)
+	}
+	return bundle
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+)
+
+const operatorConfigurationKind = "OperatorConfiguration"
+
+var (
+	// ErrUnsupportedVersion is returned when the configuration file isn't an OperatorConfiguration of a known version.
+	ErrUnsupportedVersion = errors.New("unsupported kind or API version")
+)
+
+// Load reads the operator's configuration file at the given path.
+func Load(path string) (*configv1alpha1.OperatorConfiguration, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+	return parse(content)
+}
+
+func parse(content []byte) (*configv1alpha1.OperatorConfiguration, error) {
+	file := &configv1alpha1.OperatorConfiguration{}
+	if err := yaml.UnmarshalStrict(content, file); err != nil {
+		return nil, fmt.Errorf("failed to parse the configuration file: %w", err)
+	}
+
+	if file.APIVersion != configv1alpha1.GroupVersion.String() || file.Kind != operatorConfigurationKind {
+		return nil, fmt.Errorf("%w: %s %s, expected %s %s", ErrUnsupportedVersion, file.Kind, file.APIVersion, operatorConfigurationKind, configv1alpha1.GroupVersion)
+	}
+
+	switch file.WebhookCertificates {
+	case "", configv1alpha1.WebhookCertificatesCertManager, configv1alpha1.WebhookCertificatesSelfManaged:
+	default:
+		return nil, fmt.Errorf("invalid webhookCertificates %q, expected %q or %q", file.WebhookCertificates, configv1alpha1.WebhookCertificatesCertManager, configv1alpha1.WebhookCertificatesSelfManaged)
+	}
+
+	if len(file.CacheNamespace) > 0 && len(file.WatchNamespaces) > 0 {
+		return nil, errors.New("cacheNamespace and watchNamespaces are mutually exclusive")
+	}
+
+	if err := validateImageSettings(file.Images); err != nil {
+		return nil, err
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(file.NamespaceSelector); err != nil {
+		return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+	}
+
+	// the namespaces are watched cluster-wide to select them
+	if file.NamespaceSelector != nil && (len(file.CacheNamespace) > 0 || len(file.WatchNamespaces) > 0) {
+		return nil, errors.New("namespaceSelector can't be combined with cacheNamespace nor watchNamespaces")
+	}
+
+	if file.AutoDetectFrequency != nil && file.AutoDetectFrequency.Duration <= 0 {
+		return nil, fmt.Errorf("invalid autoDetectFrequency %s, it must be positive", file.AutoDetectFrequency.Duration)
+	}
+
+	return file, nil
+}
+
+// FileWatcher applies the operator's configuration file to the configuration whenever the file changes. It runs on
+// all the replicas of the operator, as the webhooks depend on the configuration too.
+type FileWatcher struct {
+	cfg      Config
+	logger   logr.Logger
+	path     string
+	interval time.Duration
+	checksum [sha256.Size]byte
+	initial  *configv1alpha1.OperatorConfiguration
+}
+
+// NewFileWatcher creates a watcher for the configuration file at the given path, checking it at the given interval.
+// The initial file is the one the operator has been started with.
+func NewFileWatcher(cfg Config, logger logr.Logger, path string, initial *configv1alpha1.OperatorConfiguration, interval time.Duration) *FileWatcher {
+	w := &FileWatcher{
+		cfg:      cfg,
+		logger:   logger,
+		path:     path,
+		interval: interval,
+		initial:  initial,
+	}
+	if content, err := ioutil.ReadFile(path); err == nil {
+		w.checksum = sha256.Sum256(content)
+	}
+	return w
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface.
+func (w *FileWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the file periodically, until the given context is done.
+func (w *FileWatcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.Reload(); err != nil {
+				w.logger.Error(err, "failed to reload the configuration file, keeping the current configuration", "path", w.path)
+			}
+		}
+	}
+}
+
+// Reload applies the configuration file if its content has changed since the last time it was applied.
+func (w *FileWatcher) Reload() error {
+	content, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("failed to read the configuration file: %w", err)
+	}
+
+	checksum := sha256.Sum256(content)
+	if checksum == w.checksum {
+		return nil
+	}
+
+	file, err := parse(content)
+	if err != nil {
+		return err
+	}
+	w.checksum = checksum
+
+	if requiresRestart(w.initial, file) {
+		w.logger.Info("the configuration file has settings that are applied only when the operator restarts", "path", w.path)
+	}
+
+	w.logger.Info("reloading the configuration file", "path", w.path)
+	w.cfg.Apply(file)
+	return nil
+}
+
+// requiresRestart returns whether the files differ in the settings that aren't reloaded, which are all the settings
+// but the collector defaults, the image rules, the namespace selector and the auto-detection frequency. The
+// distributions are read only at startup.
+func requiresRestart(initial, current *configv1alpha1.OperatorConfiguration) bool {
+	if initial == nil {
+		initial = &configv1alpha1.OperatorConfiguration{}
+	}
+	a, b := initial.DeepCopy(), current.DeepCopy()
+	for _, f := range []*configv1alpha1.OperatorConfiguration{a, b} {
+		f.Collector = configv1alpha1.CollectorDefaults{Distributions: f.Collector.Distributions}
+		f.Images = configv1alpha1.ImageSettings{}
+		f.NamespaceSelector = nil
+		f.AutoDetectFrequency = nil
+	}
+	return !apiequality.Semantic.DeepEqual(a, b)
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+)
+
+func TestLoad(t *testing.T) {
+	// test
+	file, err := config.Load("testdata/operator-config.yaml")
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", file.Metrics.BindAddress)
+	assert.True(t, *file.LeaderElection.LeaderElect)
+	assert.Equal(t, []string{"observability", "team-a"}, file.WatchNamespaces)
+	assert.Equal(t, configv1alpha1.WebhookCertificatesSelfManaged, file.WebhookCertificates)
+	assert.False(t, configv1alpha1.Enabled(file.Features.Upgrades))
+	assert.True(t, configv1alpha1.Enabled(file.Features.Webhooks))
+}
+
+func TestLoadInvalidFiles(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{"other kind", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: Other\n", "unsupported kind"},
+		{"other version", "apiVersion: config.opentelemetry.io/v1\nkind: OperatorConfiguration\n", "unsupported kind"},
+		{"unknown field", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\ncollectors: {}\n", "unknown field"},
+		{"invalid webhook certificates", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nwebhookCertificates: vault\n", "webhookCertificates"},
+		{"invalid namespace selector", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nnamespaceSelector:\n  matchExpressions: [{key: team, operator: Near}]\n", "namespaceSelector"},
+		{"namespace selector and watch namespaces", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nnamespaceSelector: {}\nwatchNamespaces: [a]\n", "can't be combined"},
+		{"image rewrite without target", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nimages:\n  rewrites: [{from: docker.io/}]\n", "images.rewrites[0]"},
+		{"invalid image digest", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nimages:\n  digests:\n    docker.io/library/busybox:1.33: latest\n", "invalid digest"},
+		{"zero auto-detect frequency", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nautoDetectFrequency: 0s\n", "autoDetectFrequency"},
+		{"negative auto-detect frequency", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\nautoDetectFrequency: -5s\n", "autoDetectFrequency"},
+		{"cache namespace and watch namespaces", "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\ncacheNamespace: a\nwatchNamespaces: [b]\n", "mutually exclusive"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.content), 0600))
+
+			// test
+			_, err := config.Load(path)
+
+			// verify
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	// test
+	_, err := config.Load("testdata/missing.yaml")
+
+	// verify
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, config.ErrUnsupportedVersion))
+}
+
+func TestFileWatcherReload(t *testing.T) {
+	// prepare
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(image string) {
+		content := "apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\ncollector:\n  image: " + image + "\n"
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	write("first")
+
+	calledBack := 0
+	cfg := config.New(config.WithOnChange(func() error {
+		calledBack++
+		return nil
+	}))
+	file, err := config.Load(path)
+	require.NoError(t, err)
+	cfg.Apply(file)
+	watcher := config.NewFileWatcher(cfg, logf.Log.WithName("unit-tests"), path, file, time.Minute)
+
+	// test
+	require.NoError(t, watcher.Reload())
+	write("second")
+	require.NoError(t, watcher.Reload())
+
+	// verify
+	assert.Equal(t, "second", cfg.CollectorImage())
+	assert.Equal(t, 2, calledBack)
+	assert.False(t, watcher.NeedLeaderElection())
+}
+
+func TestFileWatcherKeepsTheConfigurationOnErrors(t *testing.T) {
+	// prepare
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("apiVersion: config.opentelemetry.io/v1alpha1\nkind: OperatorConfiguration\ncollector:\n  image: first\n"), 0600))
+	cfg := config.New()
+	file, err := config.Load(path)
+	require.NoError(t, err)
+	cfg.Apply(file)
+	watcher := config.NewFileWatcher(cfg, logf.Log.WithName("unit-tests"), path, file, time.Minute)
+	require.NoError(t, ioutil.WriteFile(path, []byte("kind: Other\n"), 0600))
+
+	// test
+	err = watcher.Reload()
+
+	// verify
+	assert.True(t, errors.Is(err, config.ErrUnsupportedVersion))
+	assert.Equal(t, "first", cfg.CollectorImage())
+}
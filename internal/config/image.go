@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+)
+
+// digestPattern matches the digests of the OCI image specification, like sha256:0123...
+var digestPattern = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+
+// qualifiedImage returns the fully qualified reference of the given image, making the registry and the repository of
+// the images from Docker Hub explicit, like docker.io/library/busybox:1.33 for busybox:1.33.
+func qualifiedImage(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		return "docker.io/" + image
+	}
+	return image
+}
+
+// rewriteImage applies the given rules to the image. The image is returned as it is when no rules apply to it.
+func rewriteImage(settings configv1alpha1.ImageSettings, image string) string {
+	if len(image) == 0 {
+		return image
+	}
+
+	qualified := qualifiedImage(image)
+	changed := false
+	for _, rule := range settings.Rewrites {
+		if strings.HasPrefix(qualified, rule.From) {
+			qualified = rule.To + strings.TrimPrefix(qualified, rule.From)
+			changed = true
+			break
+		}
+	}
+
+	if digest, ok := settings.Digests[qualified]; ok && !strings.Contains(qualified, "@") {
+		qualified = fmt.Sprintf("%s@%s", qualified, digest)
+		changed = true
+	}
+
+	if !changed {
+		return image
+	}
+	return qualified
+}
+
+// validateImageSettings returns an error when the rules can't be applied.
+func validateImageSettings(settings configv1alpha1.ImageSettings) error {
+	for i, rule := range settings.Rewrites {
+		if len(rule.From) == 0 || len(rule.To) == 0 {
+			return fmt.Errorf("invalid images.rewrites[%d], both from and to are required", i)
+		}
+	}
+	for image, digest := range settings.Digests {
+		if !digestPattern.MatchString(digest) {
+			return fmt.Errorf("invalid digest %q for the image %s, expected a digest like sha256:0123...", digest, image)
+		}
+	}
+	for i, secret := range settings.PullSecrets {
+		if len(secret.Name) == 0 {
+			return fmt.Errorf("invalid images.pullSecrets[%d], the name is required", i)
+		}
+	}
+	return nil
+}
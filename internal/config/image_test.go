@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+)
+
+func TestRewriteImage(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Images: configv1alpha1.ImageSettings{
+			Rewrites: []configv1alpha1.ImageRewrite{
+				{From: "docker.io/otel/", To: "registry.example.com/otel/"},
+				{From: "docker.io/", To: "registry.example.com/mirror/"},
+				{From: "quay.io/", To: "registry.example.com/quay/"},
+			},
+			Digests: map[string]string{
+				"registry.example.com/otel/opentelemetry-collector:0.31.0": "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+		},
+	})
+
+	for _, tt := range []struct {
+		image    string
+		expected string
+	}{
+		{"otel/opentelemetry-collector:0.31.0", "registry.example.com/otel/opentelemetry-collector:0.31.0@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{"otel/opentelemetry-collector:0.30.0", "registry.example.com/otel/opentelemetry-collector:0.30.0"},
+		{"busybox:1.33", "registry.example.com/mirror/library/busybox:1.33"},
+		{"quay.io/jaegertracing/jaeger-agent:1.24", "registry.example.com/quay/jaegertracing/jaeger-agent:1.24"},
+		{"ghcr.io/example/app:1.0", "ghcr.io/example/app:1.0"},
+		{"localhost:5000/app:1.0", "localhost:5000/app:1.0"},
+		{"", ""},
+	} {
+		t.Run(tt.image, func(t *testing.T) {
+			// test
+			image := cfg.RewriteImage(tt.image)
+
+			// verify
+			assert.Equal(t, tt.expected, image)
+		})
+	}
+}
+
+func TestRewriteImageWithoutRules(t *testing.T) {
+	// prepare
+	cfg := config.New()
+
+	// test
+	image := cfg.RewriteImage("otel/opentelemetry-collector:0.31.0")
+
+	// verify
+	assert.Equal(t, "otel/opentelemetry-collector:0.31.0", image)
+	assert.Empty(t, cfg.ImagePullSecrets())
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	secrets := []corev1.LocalObjectReference{{Name: "registry-credentials"}}
+
+	// test
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Images: configv1alpha1.ImageSettings{PullSecrets: secrets},
+	})
+
+	// verify
+	assert.Equal(t, secrets, cfg.ImagePullSecrets())
+}
@@ -16,13 +16,23 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/version"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/autodetect"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
@@ -38,16 +48,44 @@ type Config struct {
 	// Registers a callback, to be called once a configuration change happens
 	OnChange func() error
 
-	logger              logr.Logger
-	autoDetect          autodetect.AutoDetect
-	autoDetectFrequency time.Duration
-	onChange            []func() error
+	logger     logr.Logger
+	autoDetect autodetect.AutoDetect
+	onChange   []func() error
+	version    version.Version
 
-	// config state
+	// the auto-detected state and the settings are shared by the copies of the configuration, like the ones held by
+	// the reconcilers, so that they see the changes
+	detected *detected
+	settings *settings
+}
+
+type detected struct {
+	mu           sync.RWMutex
+	platform     platform.Platform
+	capabilities autodetect.Capabilities
+}
+
+type settings struct {
+	mu sync.RWMutex
+
+	// the values from the options and the flags, the flags set explicitly take precedence over the file
+	base  values
+	flags *pflag.FlagSet
+
+	// the operator's configuration file, once applied, and the namespace selector it holds
+	file              *configv1alpha1.OperatorConfiguration
+	namespaceSelector labels.Selector
+
+	// applied is signaled when another configuration file is applied, so that the auto-detection picks up the new
+	// frequency
+	applied chan struct{}
+}
+
+type values struct {
+	autoDetectFrequency     time.Duration
 	collectorImage          string
 	collectorConfigMapEntry string
-	platform                platform.Platform
-	version                 version.Version
+	collectorDistributions  string
 }
 
 // New constructs a new configuration based on the given options.
@@ -71,25 +109,38 @@ func New(opts ...Option) Config {
 	}
 
 	return Config{
-		autoDetect:              o.autoDetect,
-		autoDetectFrequency:     o.autoDetectFrequency,
-		collectorImage:          o.collectorImage,
-		collectorConfigMapEntry: o.collectorConfigMapEntry,
-		logger:                  o.logger,
-		onChange:                o.onChange,
-		platform:                o.platform,
-		version:                 o.version,
+		autoDetect: o.autoDetect,
+		logger:     o.logger,
+		onChange:   o.onChange,
+		version:    o.version,
+		detected:   &detected{platform: o.platform, capabilities: o.capabilities},
+		settings: &settings{
+			base: values{
+				autoDetectFrequency:     o.autoDetectFrequency,
+				collectorImage:          o.collectorImage,
+				collectorConfigMapEntry: o.collectorConfigMapEntry,
+				collectorDistributions:  o.collectorDistributions,
+			},
+			applied: make(chan struct{}, 1),
+		},
 	}
 }
 
-// FlagSet binds the flags to the user-modifiable values of the operator's configuration.
+// FlagSet binds the flags to the user-modifiable values of the operator's configuration. The flags set explicitly
+// take precedence over the configuration file.
 func (c *Config) FlagSet() *pflag.FlagSet {
 	fs := pflag.NewFlagSet("opentelemetry-operator", pflag.ExitOnError)
-	pflag.StringVar(&c.collectorImage,
+	c.settings.flags = fs
+	fs.StringVar(&c.settings.base.collectorImage,
 		"otelcol-image",
-		c.collectorImage,
+		c.settings.base.collectorImage,
 		"The default image to use for OpenTelemetry Collector when not specified in the individual custom resource (CR)",
 	)
+	fs.StringVar(&c.settings.base.collectorDistributions,
+		"collector-distributions",
+		c.settings.base.collectorDistributions,
+		"The path to a file with additional OpenTelemetry Collector distributions, along with the components they include",
+	)
 
 	return fs
 }
@@ -104,60 +155,289 @@ func (c *Config) StartAutoDetect() error {
 }
 
 func (c *Config) periodicAutoDetect() {
-	ticker := time.NewTicker(c.autoDetectFrequency)
+	var applied <-chan struct{}
+	if c.settings != nil {
+		applied = c.settings.applied
+	}
+
+	frequency := c.AutoDetectFrequency()
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := c.AutoDetect(); err != nil {
-			c.logger.Info("auto-detection failed", "error", err)
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.AutoDetect(); err != nil {
+				c.logger.Info("auto-detection failed", "error", err)
+			}
+		case <-applied:
+			// the frequency changes when the configuration file is reloaded
+			if f := c.AutoDetectFrequency(); f != frequency {
+				c.logger.V(1).Info("auto-detection frequency changed", "frequency", f)
+				frequency = f
+				ticker.Reset(frequency)
+			}
 		}
 	}
 }
 
-// AutoDetect attempts to automatically detect relevant information for this operator.
+// AutoDetect attempts to automatically detect relevant information for this operator. The callbacks are notified
+// when the platform or the capabilities of the cluster have changed since the previous run.
 func (c *Config) AutoDetect() error {
-	changed := false
 	c.logger.V(2).Info("auto-detecting the configuration based on the environment")
 
-	// TODO: once new things need to be detected, extract this into individual detection routines
-	if c.platform == platform.Unknown {
-		plt, err := c.autoDetect.Platform()
-		if err != nil {
-			return err
-		}
+	plt, err := c.autoDetect.Platform()
+	if err != nil {
+		return err
+	}
 
-		if c.platform != plt {
-			c.logger.V(1).Info("platform detected", "platform", plt)
-			c.platform = plt
-			changed = true
-		}
+	capabilities, err := c.autoDetect.Capabilities()
+	if err != nil {
+		return err
 	}
 
+	changed := false
+	c.detected.mu.Lock()
+	if plt != platform.Unknown && plt != c.detected.platform {
+		c.logger.V(1).Info("platform detected", "platform", plt)
+		c.detected.platform = plt
+		changed = true
+	}
+	if capabilities != c.detected.capabilities {
+		c.logger.V(1).Info("capabilities changed", "capabilities", capabilities)
+		c.detected.capabilities = capabilities
+		changed = true
+	}
+	c.detected.mu.Unlock()
+
 	if changed {
-		for _, callback := range c.onChange {
-			if err := callback(); err != nil {
-				// we don't fail if the callback failed, as the auto-detection itself
-				// did work
-				c.logger.Error(err, "configuration change notification failed for callback")
+		c.notifyChange()
+	}
+
+	return nil
+}
+
+// Apply sets the operator's configuration file, whose settings take precedence over the options. The callbacks are
+// notified when the settings have changed, so that the instances get the new defaults.
+func (c *Config) Apply(file *configv1alpha1.OperatorConfiguration) {
+	c.settings.mu.Lock()
+	changed := !apiequality.Semantic.DeepEqual(c.settings.file, file)
+	if changed {
+		c.settings.file = file.DeepCopy()
+		c.settings.namespaceSelector = nil
+		if file != nil && file.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(file.NamespaceSelector)
+			if err != nil {
+				// the files are validated when loaded, this is only about the files built programmatically
+				c.logger.Error(err, "invalid namespace selector, no namespaces are selected")
+				selector = labels.Nothing()
 			}
+			c.settings.namespaceSelector = selector
 		}
 	}
+	c.settings.mu.Unlock()
 
-	return nil
+	if changed {
+		c.logger.V(1).Info("configuration file applied")
+		select {
+		case c.settings.applied <- struct{}{}:
+		default:
+			// a signal is already pending
+		}
+		c.notifyChange()
+	}
+}
+
+func (c *Config) notifyChange() {
+	for _, callback := range c.onChange {
+		if err := callback(); err != nil {
+			// we don't fail if the callback failed, as the change itself did work
+			c.logger.Error(err, "configuration change notification failed for callback")
+		}
+	}
+}
+
+// current returns the values in effect, along with the collector defaults from the configuration file.
+func (c *Config) current() (values, configv1alpha1.CollectorDefaults) {
+	if c.settings == nil {
+		return values{}, configv1alpha1.CollectorDefaults{}
+	}
+	c.settings.mu.RLock()
+	defer c.settings.mu.RUnlock()
+
+	v := c.settings.base
+	file := c.settings.file
+	if file == nil {
+		return v, configv1alpha1.CollectorDefaults{}
+	}
+
+	if file.AutoDetectFrequency != nil && file.AutoDetectFrequency.Duration > 0 {
+		v.autoDetectFrequency = file.AutoDetectFrequency.Duration
+	}
+	if len(file.Collector.Image) > 0 && !c.settings.flagChanged("otelcol-image") {
+		v.collectorImage = file.Collector.Image
+	}
+	if len(file.Collector.ConfigMapEntry) > 0 {
+		v.collectorConfigMapEntry = file.Collector.ConfigMapEntry
+	}
+	if len(file.Collector.Distributions) > 0 && !c.settings.flagChanged("collector-distributions") {
+		v.collectorDistributions = file.Collector.Distributions
+	}
+
+	return v, *file.Collector.DeepCopy()
+}
+
+func (s *settings) flagChanged(name string) bool {
+	return s.flags != nil && s.flags.Changed(name)
 }
 
 // CollectorImage represents the flag to override the OpenTelemetry Collector container image.
 func (c *Config) CollectorImage() string {
-	return c.collectorImage
+	v, _ := c.current()
+	return v.collectorImage
+}
+
+// CollectorImageForMode represents the default OpenTelemetry Collector container image for instances in the given
+// mode, which is the default image unless the configuration file has one for the mode.
+func (c *Config) CollectorImageForMode(mode v1alpha1.Mode) string {
+	v, defaults := c.current()
+
+	var image string
+	switch mode {
+	case v1alpha1.ModeDeployment:
+		image = defaults.Images.Deployment
+	case v1alpha1.ModeDaemonSet:
+		image = defaults.Images.DaemonSet
+	case v1alpha1.ModeStatefulSet:
+		image = defaults.Images.StatefulSet
+	case v1alpha1.ModeSidecar:
+		image = defaults.Images.Sidecar
+	}
+
+	if len(image) == 0 {
+		return v.collectorImage
+	}
+	return image
 }
 
-// CollectorConfigMapEntry represents the configuration file name for the collector. Immutable.
+// CollectorResources represents the default compute resources of the OpenTelemetry Collector container.
+func (c *Config) CollectorResources() corev1.ResourceRequirements {
+	_, defaults := c.current()
+	if defaults.Resources == nil {
+		return corev1.ResourceRequirements{}
+	}
+	return *defaults.Resources
+}
+
+// CollectorLabels represents the labels added to the workloads and pods of the OpenTelemetry Collector instances.
+func (c *Config) CollectorLabels() map[string]string {
+	_, defaults := c.current()
+	return defaults.Labels
+}
+
+// CollectorAnnotations represents the annotations added to the workloads and pods of the OpenTelemetry Collector
+// instances.
+func (c *Config) CollectorAnnotations() map[string]string {
+	_, defaults := c.current()
+	return defaults.Annotations
+}
+
+// RewriteImage returns the given image with the rewrite rules and the digests from the configuration file applied.
+func (c *Config) RewriteImage(image string) string {
+	return rewriteImage(c.imageSettings(), image)
+}
+
+// ImagePullSecrets represents the image pull secrets added to the pods of all the collectors.
+func (c *Config) ImagePullSecrets() []corev1.LocalObjectReference {
+	return c.imageSettings().PullSecrets
+}
+
+func (c *Config) imageSettings() configv1alpha1.ImageSettings {
+	if c.settings == nil {
+		return configv1alpha1.ImageSettings{}
+	}
+	c.settings.mu.RLock()
+	defer c.settings.mu.RUnlock()
+	if c.settings.file == nil {
+		return configv1alpha1.ImageSettings{}
+	}
+	return *c.settings.file.Images.DeepCopy()
+}
+
+// CollectorConfigMapEntry represents the configuration file name for the collector.
 func (c *Config) CollectorConfigMapEntry() string {
-	return c.collectorConfigMapEntry
+	v, _ := c.current()
+	return v.collectorConfigMapEntry
+}
+
+// CollectorDistributions represents the path to a file with additional OpenTelemetry Collector distributions.
+func (c *Config) CollectorDistributions() string {
+	v, _ := c.current()
+	return v.collectorDistributions
+}
+
+// AutoDetectFrequency represents how often the platform and the capabilities of the cluster are detected. The default
+// frequency is used when the one from the options isn't positive.
+func (c *Config) AutoDetectFrequency() time.Duration {
+	v, _ := c.current()
+	if v.autoDetectFrequency <= 0 {
+		return defaultAutoDetectFrequency
+	}
+	return v.autoDetectFrequency
+}
+
+// NamespaceMatches represents whether the instances in the given namespace are managed by the operator, and whether
+// its pods get sidecars, according to the namespace selector from the configuration file.
+func (c *Config) NamespaceMatches(ns corev1.Namespace) bool {
+	selector := c.namespaceSelector()
+	return selector == nil || selector.Matches(labels.Set(ns.Labels))
+}
+
+// NamespaceInScope returns whether the instances in the namespace with the given name are managed by the operator.
+// The namespace is retrieved with the given reader only when there's a namespace selector, and the namespaces that
+// don't exist are out of scope.
+func (c *Config) NamespaceInScope(ctx context.Context, reader client.Reader, name string) (bool, error) {
+	if c.namespaceSelector() == nil {
+		return true, nil
+	}
+
+	ns := corev1.Namespace{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: name}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the namespace %s: %w", name, err)
+	}
+	return c.NamespaceMatches(ns), nil
+}
+
+func (c *Config) namespaceSelector() labels.Selector {
+	if c.settings == nil {
+		return nil
+	}
+	c.settings.mu.RLock()
+	defer c.settings.mu.RUnlock()
+	return c.settings.namespaceSelector
 }
 
 // Platform represents the type of the platform this operator is running.
 func (c *Config) Platform() platform.Platform {
-	return c.platform
+	if c.detected == nil {
+		return platform.Unknown
+	}
+	c.detected.mu.RLock()
+	defer c.detected.mu.RUnlock()
+	return c.detected.platform
+}
+
+// Capabilities represents the capabilities of the cluster this operator is running on, like the available APIs.
+func (c *Config) Capabilities() autodetect.Capabilities {
+	if c.detected == nil {
+		return autodetect.Capabilities{}
+	}
+	c.detected.mu.RLock()
+	defer c.detected.mu.RUnlock()
+	return c.detected.capabilities
 }
 
 // Version holds the versions used by this operator.
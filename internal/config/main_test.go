@@ -15,13 +15,22 @@
 package config_test
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/internal/version"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/autodetect"
@@ -33,15 +42,102 @@ func TestNewConfig(t *testing.T) {
 	cfg := config.New(
 		config.WithCollectorImage("some-image"),
 		config.WithCollectorConfigMapEntry("some-config.yaml"),
+		config.WithCollectorDistributions("distributions.yaml"),
 		config.WithPlatform(platform.Kubernetes),
 	)
 
 	// test
 	assert.Equal(t, "some-image", cfg.CollectorImage())
 	assert.Equal(t, "some-config.yaml", cfg.CollectorConfigMapEntry())
+	assert.Equal(t, "distributions.yaml", cfg.CollectorDistributions())
 	assert.Equal(t, platform.Kubernetes, cfg.Platform())
 }
 
+func TestCollectorDistributionsFlag(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	fs := cfg.FlagSet()
+
+	// test
+	err := fs.Parse([]string{"--collector-distributions=/etc/otelcol/distributions.yaml"})
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/otelcol/distributions.yaml", cfg.CollectorDistributions())
+}
+
+func TestCollectorImageFlag(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	fs := cfg.FlagSet()
+
+	// test
+	err := fs.Parse([]string{"--otelcol-image=registry.example.com/otelcol:0.31.0"})
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/otelcol:0.31.0", cfg.CollectorImage())
+}
+
+func TestApplyConfigurationFile(t *testing.T) {
+	// prepare
+	cfg := config.New(config.WithCollectorImage("default-image"), config.WithCollectorConfigMapEntry("collector.yaml"))
+	file, err := config.Load("testdata/operator-config.yaml")
+	require.NoError(t, err)
+
+	// test
+	cfg.Apply(file)
+
+	// verify
+	assert.Equal(t, "registry.example.com/otelcol:0.31.0", cfg.CollectorImage())
+	assert.Equal(t, "registry.example.com/otelcol:0.31.0", cfg.CollectorImageForMode(v1alpha1.ModeDeployment))
+	assert.Equal(t, "registry.example.com/otelcol-sidecar:0.31.0", cfg.CollectorImageForMode(v1alpha1.ModeSidecar))
+	assert.Equal(t, resource.MustParse("256Mi"), cfg.CollectorResources().Limits[corev1.ResourceMemory])
+	assert.Equal(t, map[string]string{"team": "observability"}, cfg.CollectorLabels())
+	assert.Equal(t, map[string]string{"example.com/owner": "observability"}, cfg.CollectorAnnotations())
+	assert.Equal(t, "config.yaml", cfg.CollectorConfigMapEntry())
+	assert.Equal(t, 30*time.Second, cfg.AutoDetectFrequency())
+}
+
+func TestFlagsTakePrecedenceOverTheFile(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	fs := cfg.FlagSet()
+	require.NoError(t, fs.Parse([]string{"--otelcol-image=from-flag"}))
+
+	// test
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Collector: configv1alpha1.CollectorDefaults{
+			Image:         "from-file",
+			Distributions: "distributions.yaml",
+		},
+	})
+
+	// verify
+	assert.Equal(t, "from-flag", cfg.CollectorImage())
+	assert.Equal(t, "distributions.yaml", cfg.CollectorDistributions())
+}
+
+func TestApplyNotifiesChanges(t *testing.T) {
+	// prepare
+	calledBack := 0
+	cfg := config.New(config.WithOnChange(func() error {
+		calledBack++
+		return nil
+	}))
+	cp := cfg
+	file := &configv1alpha1.OperatorConfiguration{Collector: configv1alpha1.CollectorDefaults{Image: "first"}}
+
+	// test
+	cfg.Apply(file)
+	cfg.Apply(file)
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{Collector: configv1alpha1.CollectorDefaults{Image: "second"}})
+
+	// verify
+	assert.Equal(t, 2, calledBack)
+	assert.Equal(t, "second", cp.CollectorImage())
+}
+
 func TestOverrideVersion(t *testing.T) {
 	// prepare
 	v := version.Version{
@@ -81,13 +177,87 @@ func TestCallbackOnChanges(t *testing.T) {
 	assert.True(t, calledBack)
 }
 
+func TestCapabilitiesDetection(t *testing.T) {
+	// prepare
+	capabilities := autodetect.Capabilities{ServerVersion: "v1.21.2", CertManager: true}
+	calledBack := 0
+	mock := &mockAutoDetect{
+		CapabilitiesFunc: func() (autodetect.Capabilities, error) {
+			return capabilities, nil
+		},
+	}
+	cfg := config.New(
+		config.WithAutoDetect(mock),
+		config.WithPlatform(platform.Kubernetes),
+		config.WithOnChange(func() error {
+			calledBack++
+			return nil
+		}),
+	)
+
+	// test
+	require.NoError(t, cfg.AutoDetect())
+	assert.True(t, cfg.Capabilities().CertManager)
+
+	require.NoError(t, cfg.AutoDetect())
+	capabilities.CertManager = false
+	capabilities.PDBVersion = "policy/v1"
+	require.NoError(t, cfg.AutoDetect())
+
+	// verify
+	assert.Equal(t, autodetect.Capabilities{ServerVersion: "v1.21.2", PDBVersion: "policy/v1"}, cfg.Capabilities())
+	assert.Equal(t, 2, calledBack)
+}
+
+func TestKeepCapabilitiesOnError(t *testing.T) {
+	// prepare
+	mock := &mockAutoDetect{
+		CapabilitiesFunc: func() (autodetect.Capabilities, error) {
+			return autodetect.Capabilities{}, errors.New("the API server is unavailable")
+		},
+	}
+	cfg := config.New(
+		config.WithAutoDetect(mock),
+		config.WithCapabilities(autodetect.Capabilities{Istio: true}),
+	)
+
+	// test
+	err := cfg.AutoDetect()
+
+	// verify
+	assert.Error(t, err)
+	assert.True(t, cfg.Capabilities().Istio)
+}
+
+func TestDetectedStateIsSharedWithCopies(t *testing.T) {
+	// prepare
+	mock := &mockAutoDetect{
+		PlatformFunc: func() (platform.Platform, error) {
+			return platform.OpenShift, nil
+		},
+	}
+	cfg := config.New(config.WithAutoDetect(mock))
+	cp := cfg
+
+	// test
+	err := cfg.AutoDetect()
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, platform.OpenShift, cp.Platform())
+}
+
 func TestAutoDetectInBackground(t *testing.T) {
 	// prepare
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
+	calls := int32(0)
 	mock := &mockAutoDetect{
 		PlatformFunc: func() (platform.Platform, error) {
-			wg.Done()
+			// the auto-detection keeps on running once the test is done
+			if atomic.AddInt32(&calls, 1) <= 2 {
+				wg.Done()
+			}
 			// returning Unknown will cause the auto-detection to keep trying to detect the platform
 			return platform.Unknown, nil
 		},
@@ -108,10 +278,57 @@ func TestAutoDetectInBackground(t *testing.T) {
 	wg.Wait()
 }
 
+func TestAutoDetectFrequencyFromTheReloadedFile(t *testing.T) {
+	// prepare
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+	calls := int32(0)
+	mock := &mockAutoDetect{
+		PlatformFunc: func() (platform.Platform, error) {
+			if atomic.AddInt32(&calls, 1) <= 3 {
+				wg.Done()
+			}
+			return platform.Unknown, nil
+		},
+	}
+	cfg := config.New(
+		config.WithAutoDetect(mock),
+		config.WithAutoDetectFrequency(time.Hour),
+	)
+	require.NoError(t, cfg.StartAutoDetect())
+
+	// test
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{AutoDetectFrequency: &metav1.Duration{Duration: 50 * time.Millisecond}})
+
+	// verify
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the auto-detection didn't run with the frequency from the file")
+	}
+}
+
+func TestDefaultAutoDetectFrequency(t *testing.T) {
+	// prepare
+	cfg := config.New(config.WithAutoDetectFrequency(0))
+
+	// test
+	frequency := cfg.AutoDetectFrequency()
+
+	// verify
+	assert.Equal(t, 5*time.Second, frequency)
+}
+
 var _ autodetect.AutoDetect = (*mockAutoDetect)(nil)
 
 type mockAutoDetect struct {
-	PlatformFunc func() (platform.Platform, error)
+	PlatformFunc     func() (platform.Platform, error)
+	CapabilitiesFunc func() (autodetect.Capabilities, error)
 }
 
 func (m *mockAutoDetect) Platform() (platform.Platform, error) {
@@ -120,3 +337,44 @@ func (m *mockAutoDetect) Platform() (platform.Platform, error) {
 	}
 	return platform.Unknown, nil
 }
+
+func (m *mockAutoDetect) Capabilities() (autodetect.Capabilities, error) {
+	if m.CapabilitiesFunc != nil {
+		return m.CapabilitiesFunc()
+	}
+	return autodetect.Capabilities{}, nil
+}
+
+func TestNamespaceSelector(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	cl := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"opentelemetry": "enabled"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+	).Build()
+
+	// sanity check
+	inScope, err := cfg.NamespaceInScope(context.Background(), cl, "team-b")
+	require.NoError(t, err)
+	require.True(t, inScope)
+
+	// test
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"opentelemetry": "enabled"}},
+	})
+
+	// verify
+	for _, tt := range []struct {
+		namespace string
+		expected  bool
+	}{
+		{"team-a", true},
+		{"team-b", false},
+		{"missing", false},
+	} {
+		inScope, err := cfg.NamespaceInScope(context.Background(), cl, tt.namespace)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, inScope, tt.namespace)
+	}
+	assert.False(t, cfg.NamespaceMatches(corev1.Namespace{}))
+}
@@ -30,8 +30,10 @@ type Option func(c *options)
 type options struct {
 	autoDetect              autodetect.AutoDetect
 	autoDetectFrequency     time.Duration
+	capabilities            autodetect.Capabilities
 	collectorImage          string
 	collectorConfigMapEntry string
+	collectorDistributions  string
 	logger                  logr.Logger
 	onChange                []func() error
 	platform                platform.Platform
@@ -48,6 +50,11 @@ func WithAutoDetectFrequency(t time.Duration) Option {
 		o.autoDetectFrequency = t
 	}
 }
+func WithCapabilities(c autodetect.Capabilities) Option {
+	return func(o *options) {
+		o.capabilities = c
+	}
+}
 func WithCollectorImage(s string) Option {
 	return func(o *options) {
 		o.collectorImage = s
@@ -58,6 +65,11 @@ func WithCollectorConfigMapEntry(s string) Option {
 		o.collectorConfigMapEntry = s
 	}
 }
+func WithCollectorDistributions(s string) Option {
+	return func(o *options) {
+		o.collectorDistributions = s
+	}
+}
 func WithLogger(logger logr.Logger) Option {
 	return func(o *options) {
 		o.logger = logger
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podinjector_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	. "github.com/open-telemetry/opentelemetry-operator/internal/podinjector"
+)
+
+func TestInjectAgentEndpoint(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		annotation string
+		mode       v1alpha1.Mode
+		expected   bool
+	}{
+		{"agent from another namespace", "observability/agent", v1alpha1.ModeDaemonSet, true},
+		{"not a daemonset", "observability/agent", v1alpha1.ModeDeployment, false},
+		{"missing agent", "observability/other", v1alpha1.ModeDaemonSet, false},
+		{"disabled", "false", v1alpha1.ModeDaemonSet, false},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}},
+				&v1alpha1.OpenTelemetryCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "observability"},
+					Spec:       v1alpha1.OpenTelemetryCollectorSpec{Mode: tt.mode},
+				},
+			).Build()
+
+			decoder, err := admission.NewDecoder(scheme)
+			require.NoError(t, err)
+			injector := NewPodSidecarInjector(config.New(), logger, cl, record.NewFakeRecorder(10))
+			require.NoError(t, injector.InjectDecoder(decoder))
+
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"agent.opentelemetry.io/inject": tt.annotation},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "my-app"}},
+				},
+			}
+			encoded, err := json.Marshal(pod)
+			require.NoError(t, err)
+			req := admission.Request{
+				AdmissionRequest: admv1.AdmissionRequest{
+					Namespace: "my-app",
+					Object:    runtime.RawExtension{Raw: encoded},
+				},
+			}
+
+			// test
+			res := injector.Handle(context.Background(), req)
+
+			// verify
+			assert.True(t, res.Allowed)
+			patches, err := json.Marshal(res.Patches)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, strings.Contains(string(patches), "OTEL_NODE_IP"))
+		})
+	}
+}
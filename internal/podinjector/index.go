@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podinjector
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+// ModeIndexField is the name of the field index with the mode of the OpenTelemetryCollector instances.
+const ModeIndexField = "spec.mode"
+
+// RegisterIndexes adds the field indexes used by the injector to the given indexer, usually the manager's cache.
+// This has to be called before the manager is started.
+func RegisterIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &v1alpha1.OpenTelemetryCollector{}, ModeIndexField, indexMode)
+}
+
+func indexMode(obj client.Object) []string {
+	otelcol, ok := obj.(*v1alpha1.OpenTelemetryCollector)
+	if !ok {
+		return nil
+	}
+	return []string{string(otelcol.Spec.Mode)}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podinjector_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/podinjector"
+)
+
+func TestRegisterModeIndex(t *testing.T) {
+	// prepare
+	indexer := &recordingIndexer{}
+
+	// test
+	err := podinjector.RegisterIndexes(context.Background(), indexer)
+
+	// verify
+	require.NoError(t, err)
+	require.Contains(t, indexer.funcs, podinjector.ModeIndexField)
+	otelcol := &v1alpha1.OpenTelemetryCollector{Spec: v1alpha1.OpenTelemetryCollectorSpec{Mode: v1alpha1.ModeSidecar}}
+	assert.Equal(t, []string{"sidecar"}, indexer.funcs[podinjector.ModeIndexField](otelcol))
+}
+
+type recordingIndexer struct {
+	funcs map[string]client.IndexerFunc
+}
+
+func (r *recordingIndexer) IndexField(_ context.Context, _ client.Object, field string, extractValue client.IndexerFunc) error {
+	if r.funcs == nil {
+		r.funcs = map[string]client.IndexerFunc{}
+	}
+	r.funcs[field] = extractValue
+	return nil
+}
@@ -19,17 +19,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/agent"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/sidecar"
 )
 
@@ -37,6 +40,7 @@ var (
 	ErrMultipleInstancesPossible = errors.New("multiple OpenTelemetry Collector instances available, cannot determine which one to select")
 	ErrNoInstancesAvailable      = errors.New("no OpenTelemetry Collector instances available")
 	ErrInstanceNotSidecar        = errors.New("the OpenTelemetry Collector's mode is not set to sidecar")
+	ErrInstanceNotDaemonSet      = errors.New("the OpenTelemetry Collector's mode is not set to daemonset")
 )
 
 // +kubebuilder:webhook:path=/mutate-v1-pod,mutating=true,failurePolicy=ignore,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=none,admissionReviewVersions=v1;v1beta1
@@ -53,18 +57,21 @@ type PodSidecarInjector interface {
 
 // the implementation.
 type podSidecarInjector struct {
-	config  config.Config
-	logger  logr.Logger
-	client  client.Client
-	decoder *admission.Decoder
+	config   config.Config
+	logger   logr.Logger
+	client   client.Client
+	recorder record.EventRecorder
+	decoder  *admission.Decoder
 }
 
-// NewPodSidecarInjector creates a new PodSidecarInjector.
-func NewPodSidecarInjector(cfg config.Config, logger logr.Logger, cl client.Client) PodSidecarInjector {
+// NewPodSidecarInjector creates a new PodSidecarInjector. The recorder reports, on the instances, the sidecars that
+// couldn't be injected.
+func NewPodSidecarInjector(cfg config.Config, logger logr.Logger, cl client.Client, recorder record.EventRecorder) PodSidecarInjector {
 	return &podSidecarInjector{
-		config: cfg,
-		logger: logger,
-		client: cl,
+		config:   cfg,
+		logger:   logger,
+		client:   cl,
+		recorder: recorder,
 	}
 }
 
@@ -82,7 +89,11 @@ func (p *podSidecarInjector) Handle(ctx context.Context, req admission.Request)
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
 
-	pod, err = p.mutate(ctx, ns, pod)
+	if !p.config.NamespaceMatches(ns) {
+		return admission.Allowed("the namespace isn't selected by the operator's namespace selector")
+	}
+
+	pod, warnings, err := p.mutate(ctx, ns, pod)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
@@ -91,7 +102,9 @@ func (p *podSidecarInjector) Handle(ctx context.Context, req admission.Request)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	res := admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	res.Warnings = warnings
+	return res
 }
 
 func (p *podSidecarInjector) InjectDecoder(d *admission.Decoder) error {
@@ -99,20 +112,32 @@ func (p *podSidecarInjector) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
-func (p *podSidecarInjector) mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+// mutate returns the pod with the sidecar or the agent's endpoint, along with the warnings for the user creating the pod.
+func (p *podSidecarInjector) mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, []string, error) {
+	pod, warnings, err := p.mutateSidecar(ctx, ns, pod)
+	if err != nil || sidecar.ExistsIn(pod) {
+		return pod, warnings, err
+	}
+
+	// the pods without sidecars might send their telemetry to the agent on their node instead
+	return p.injectAgentEndpoint(ctx, ns, pod), warnings, nil
+}
+
+func (p *podSidecarInjector) mutateSidecar(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, []string, error) {
 	logger := p.logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
 
 	// if no annotations are found at all, just return the same pod
 	annValue := sidecar.AnnotationValue(ns, pod)
 	if len(annValue) == 0 {
 		logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
-		return pod, nil
+		return pod, nil, nil
 	}
 
 	// is the annotation value 'false'? if so, we need a pod without the sidecar (ie, remove if exists)
 	if strings.EqualFold(annValue, "false") {
 		logger.V(1).Info("pod explicitly refuses sidecar injection, attempting to remove sidecar if it exists")
-		return sidecar.Remove(pod)
+		pod, err := sidecar.Remove(pod)
+		return pod, nil, err
 	}
 
 	// from this point and on, a sidecar is wanted
@@ -120,7 +145,7 @@ func (p *podSidecarInjector) mutate(ctx context.Context, ns corev1.Namespace, po
 	// check whether there's a sidecar already -- return the same pod if that's the case.
 	if sidecar.ExistsIn(pod) {
 		logger.V(1).Info("pod already has sidecar in it, skipping injection")
-		return pod, nil
+		return pod, nil, nil
 	}
 
 	// which instance should it talk to?
@@ -129,22 +154,91 @@ func (p *podSidecarInjector) mutate(ctx context.Context, ns corev1.Namespace, po
 		if err == ErrMultipleInstancesPossible || err == ErrNoInstancesAvailable || err == ErrInstanceNotSidecar {
 			// we still allow the pod to be created, but we log a message to the operator's logs
 			logger.Error(err, "failed to select an OpenTelemetry Collector instance for this pod's sidecar")
-			return pod, nil
+			return pod, nil, nil
 		}
 
 		// something else happened, better fail here
-		return pod, err
+		return pod, nil, err
 	}
 
 	// once it's been determined that a sidecar is desired, none exists yet, and we know which instance it should talk to,
 	// we should add the sidecar.
 	logger.V(1).Info("injecting sidecar into pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
-	return sidecar.Add(p.config, p.logger, otelcol, pod)
+	injected, err := sidecar.Add(p.config, p.logger, otelcol, pod)
+	var reason string
+	switch {
+	case errors.Is(err, sidecar.ErrPortClash):
+		// the sidecar wouldn't be able to bind its ports
+		reason = "PortClash"
+	case errors.Is(err, sidecar.ErrInvalidOverlay):
+		// the webhook rejects such overlays, unless it was disabled when the instance was created
+		reason = "InvalidOverlay"
+	default:
+		return injected, nil, err
+	}
+
+	// we still allow the pod to be created without the sidecar, and tell both the user creating the pod and the owner
+	// of the instance
+	logger.Error(err, "failed to inject the sidecar into this pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
+	if p.recorder != nil {
+		p.recorder.Event(&otelcol, "Warning", reason, fmt.Sprintf("The sidecar wasn't injected into the pod %s/%s: %s", ns.Name, podName(pod), err))
+	}
+	return pod, []string{fmt.Sprintf("the sidecar of the OpenTelemetry Collector %s/%s wasn't injected: %s", otelcol.Namespace, otelcol.Name, err)}, nil
+}
+
+// podName returns the name of the pod, or its name prefix when the name is generated by the API server.
+func podName(pod corev1.Pod) string {
+	if len(pod.Name) > 0 {
+		return pod.Name
+	}
+	return pod.GenerateName + "*"
+}
+
+// injectAgentEndpoint injects the endpoint of the agent selected by the pod's annotations, if any. The pod is left as
+// it is when the agent can't be found, the sidecar-less pods being allowed anyway.
+func (p *podSidecarInjector) injectAgentEndpoint(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) corev1.Pod {
+	logger := p.logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
+
+	annValue := agent.AnnotationValue(ns, pod)
+	if len(annValue) == 0 || strings.EqualFold(annValue, "false") {
+		return pod
+	}
+
+	otelcol, err := p.getAgentInstance(ctx, ns, annValue)
+	if err != nil {
+		logger.Error(err, "failed to select an OpenTelemetry Collector agent for this pod")
+		return pod
+	}
+
+	logger.V(1).Info("injecting the agent's endpoint into pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
+	return agent.Add(p.logger, otelcol, pod)
+}
+
+func (p *podSidecarInjector) getAgentInstance(ctx context.Context, ns corev1.Namespace, ann string) (v1alpha1.OpenTelemetryCollector, error) {
+	if strings.EqualFold(ann, "true") {
+		return p.selectCollectorInstance(ctx, ns, v1alpha1.ModeDaemonSet)
+	}
+
+	nsn := types.NamespacedName{Name: ann, Namespace: ns.Name}
+	if parts := strings.SplitN(ann, "/", 2); len(parts) == 2 {
+		nsn = types.NamespacedName{Name: parts[1], Namespace: parts[0]}
+	}
+
+	otelcol := v1alpha1.OpenTelemetryCollector{}
+	if err := p.client.Get(ctx, nsn, &otelcol); err != nil {
+		return otelcol, err
+	}
+
+	if otelcol.Spec.Mode != v1alpha1.ModeDaemonSet {
+		return v1alpha1.OpenTelemetryCollector{}, ErrInstanceNotDaemonSet
+	}
+
+	return otelcol, nil
 }
 
 func (p *podSidecarInjector) getCollectorInstance(ctx context.Context, ns corev1.Namespace, ann string) (v1alpha1.OpenTelemetryCollector, error) {
 	if strings.EqualFold(ann, "true") {
-		return p.selectCollectorInstance(ctx, ns)
+		return p.selectCollectorInstance(ctx, ns, v1alpha1.ModeSidecar)
 	}
 
 	otelcol := v1alpha1.OpenTelemetryCollector{}
@@ -160,29 +254,35 @@ func (p *podSidecarInjector) getCollectorInstance(ctx context.Context, ns corev1
 	return otelcol, nil
 }
 
-func (p *podSidecarInjector) selectCollectorInstance(ctx context.Context, ns corev1.Namespace) (v1alpha1.OpenTelemetryCollector, error) {
+// selectCollectorInstance returns the only instance in the given mode from the namespace.
+func (p *podSidecarInjector) selectCollectorInstance(ctx context.Context, ns corev1.Namespace, mode v1alpha1.Mode) (v1alpha1.OpenTelemetryCollector, error) {
 	var (
-		otelcols = v1alpha1.OpenTelemetryCollectorList{}
-		sidecars []v1alpha1.OpenTelemetryCollector
+		otelcols   = v1alpha1.OpenTelemetryCollectorList{}
+		candidates []v1alpha1.OpenTelemetryCollector
 	)
 
-	if err := p.client.List(ctx, &otelcols, client.InNamespace(ns.Name)); err != nil {
+	// the client is expected to be backed by a cache with the mode index, see RegisterIndexes
+	opts := []client.ListOption{
+		client.InNamespace(ns.Name),
+		client.MatchingFields{ModeIndexField: string(mode)},
+	}
+	if err := p.client.List(ctx, &otelcols, opts...); err != nil {
 		return v1alpha1.OpenTelemetryCollector{}, err
 	}
 
 	for i := range otelcols.Items {
 		coll := otelcols.Items[i]
-		if coll.Spec.Mode == v1alpha1.ModeSidecar {
-			sidecars = append(sidecars, coll)
+		if coll.Spec.Mode == mode {
+			candidates = append(candidates, coll)
 		}
 	}
 
 	switch {
-	case len(sidecars) == 0:
+	case len(candidates) == 0:
 		return v1alpha1.OpenTelemetryCollector{}, ErrNoInstancesAvailable
-	case len(sidecars) > 1:
+	case len(candidates) > 1:
 		return v1alpha1.OpenTelemetryCollector{}, ErrMultipleInstancesPossible
 	default:
-		return sidecars[0], nil
+		return candidates[0], nil
 	}
 }
@@ -26,7 +26,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -150,7 +153,7 @@ func TestShouldInjectSidecar(t *testing.T) {
 			decoder, err := admission.NewDecoder(scheme.Scheme)
 			require.NoError(t, err)
 
-			injector := NewPodSidecarInjector(cfg, logger, k8sClient)
+			injector := NewPodSidecarInjector(cfg, logger, k8sClient, record.NewFakeRecorder(10))
 			err = injector.InjectDecoder(decoder)
 			require.NoError(t, err)
 
@@ -365,7 +368,7 @@ func TestPodShouldNotBeChanged(t *testing.T) {
 			decoder, err := admission.NewDecoder(scheme.Scheme)
 			require.NoError(t, err)
 
-			injector := NewPodSidecarInjector(cfg, logger, k8sClient)
+			injector := NewPodSidecarInjector(cfg, logger, k8sClient, record.NewFakeRecorder(10))
 			err = injector.InjectDecoder(decoder)
 			require.NoError(t, err)
 
@@ -424,7 +427,7 @@ func TestFailOnInvalidRequest(t *testing.T) {
 			decoder, err := admission.NewDecoder(scheme.Scheme)
 			require.NoError(t, err)
 
-			injector := NewPodSidecarInjector(cfg, logger, k8sClient)
+			injector := NewPodSidecarInjector(cfg, logger, k8sClient, record.NewFakeRecorder(10))
 			err = injector.InjectDecoder(decoder)
 			require.NoError(t, err)
 
@@ -438,3 +441,83 @@ func TestFailOnInvalidRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestReportSkippedInjection(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		overlay  *runtime.RawExtension
+		port     int32
+		event    string
+		expected string
+	}{
+		{
+			"port clash",
+			nil,
+			8888,
+			"Warning PortClash The sidecar wasn't injected into the pod my-app/my-app-*",
+			`8888/TCP used by the container "my-app"`,
+		},
+		{
+			"invalid overlay",
+			&runtime.RawExtension{Raw: []byte(`{"spec": {"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["backend"]}]}}`)},
+			8080,
+			"Warning InvalidOverlay The sidecar wasn't injected into the pod my-app/my-app-*",
+			"can't change the fields of the pod",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			testScheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(testScheme))
+			require.NoError(t, v1alpha1.AddToScheme(testScheme))
+			cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}},
+				&v1alpha1.OpenTelemetryCollector{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-sidecar", Namespace: "my-app"},
+					Spec:       v1alpha1.OpenTelemetryCollectorSpec{Mode: v1alpha1.ModeSidecar, PodTemplateOverlay: tt.overlay},
+				},
+			).Build()
+
+			decoder, err := admission.NewDecoder(testScheme)
+			require.NoError(t, err)
+			recorder := record.NewFakeRecorder(10)
+			injector := NewPodSidecarInjector(config.New(), logger, cl, recorder)
+			require.NoError(t, injector.InjectDecoder(decoder))
+
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: "my-app-",
+					Annotations:  map[string]string{"sidecar.opentelemetry.io/inject": "true"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "my-app",
+						Ports: []corev1.ContainerPort{{ContainerPort: tt.port}},
+					}},
+				},
+			}
+			encoded, err := json.Marshal(pod)
+			require.NoError(t, err)
+			req := admission.Request{
+				AdmissionRequest: admv1.AdmissionRequest{
+					Namespace: "my-app",
+					Object:    runtime.RawExtension{Raw: encoded},
+				},
+			}
+
+			// test
+			res := injector.Handle(context.Background(), req)
+
+			// verify
+			assert.True(t, res.Allowed)
+			assert.Empty(t, res.Patches)
+			require.Len(t, res.Warnings, 1)
+			assert.Contains(t, res.Warnings[0], "my-app/my-sidecar")
+			assert.Contains(t, res.Warnings[0], tt.expected)
+			require.Len(t, recorder.Events, 1)
+			event := <-recorder.Events
+			assert.Contains(t, event, tt.event)
+			assert.Contains(t, event, tt.expected)
+		})
+	}
+}
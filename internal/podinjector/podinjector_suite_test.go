@@ -15,6 +15,7 @@
 package podinjector_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/podinjector"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,11 +52,12 @@ func TestMain(m *testing.M) {
 	}
 	// +kubebuilder:scaffold:scheme
 
-	k8sClient, err = client.New(cfg, client.Options{Scheme: testScheme})
+	c, err := client.New(cfg, client.Options{Scheme: testScheme})
 	if err != nil {
 		fmt.Printf("failed to setup a Kubernetes client: %v", err)
 		os.Exit(1)
 	}
+	k8sClient = indexedClient{Client: c}
 
 	code := m.Run()
 
@@ -66,3 +69,37 @@ func TestMain(m *testing.M) {
 
 	os.Exit(code)
 }
+
+// indexedClient emulates the mode index from the manager's cache, as the API server
+// doesn't support field selectors for custom resources.
+type indexedClient struct {
+	client.Client
+}
+
+func (c indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.FieldSelector == nil {
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	mode, found := listOpts.FieldSelector.RequiresExactMatch(podinjector.ModeIndexField)
+	listOpts.FieldSelector = nil
+	if err := c.Client.List(ctx, list, listOpts); err != nil {
+		return err
+	}
+
+	otelcols, ok := list.(*v1alpha1.OpenTelemetryCollectorList)
+	if !found || !ok {
+		return nil
+	}
+
+	var items []v1alpha1.OpenTelemetryCollector
+	for _, otelcol := range otelcols.Items {
+		if string(otelcol.Spec.Mode) == mode {
+			items = append(items, otelcol)
+		}
+	}
+	otelcols.Items = items
+	return nil
+}
@@ -0,0 +1,401 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhookcert manages the certificates of the operator's webhooks, for clusters without cert-manager.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/certificate"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;patch
+
+const (
+	// caKey holds the private key of the current certificate authority in the secret, while ca.crt holds the bundle
+	// with the current authority and, during a rotation, the previous one.
+	caKey = "ca.key"
+
+	caValidity = 10 * 365 * 24 * time.Hour
+)
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+var (
+	_ manager.Runnable               = (*rotator)(nil)
+	_ manager.LeaderElectionRunnable = (*rotator)(nil)
+	_ manager.Runnable               = (*syncer)(nil)
+	_ manager.LeaderElectionRunnable = (*syncer)(nil)
+)
+
+// Options configures where the certificates are stored and who trusts them.
+type Options struct {
+	// Namespace and SecretName identify the secret with the certificates, shared by all the replicas of the operator.
+	Namespace  string
+	SecretName string
+
+	// ServiceName is the name of the service in front of the webhooks, in the same namespace.
+	ServiceName string
+
+	// CertDir is the directory the webhook server reads its certificate from.
+	CertDir string
+
+	// MutatingWebhookConfigurations, ValidatingWebhookConfigurations and CRDs list the objects with a caBundle to keep
+	// up to date with the certificate authority.
+	MutatingWebhookConfigurations   []string
+	ValidatingWebhookConfigurations []string
+	CRDs                            []string
+
+	// Validity is the validity of the serving certificate, which is renewed when it expires within RenewBefore.
+	Validity    time.Duration
+	RenewBefore time.Duration
+
+	// CheckInterval is how often the certificate is checked for renewal.
+	CheckInterval time.Duration
+}
+
+// Manager generates the certificates of the webhooks and keeps them up to date.
+type Manager struct {
+	client client.Client
+	reader client.Reader
+	logger logr.Logger
+	opts   Options
+}
+
+// New creates a new manager for the webhooks' certificates. The reader should read directly from the API server, as
+// the manager is used before the informers' caches are started.
+func New(cl client.Client, reader client.Reader, logger logr.Logger, opts Options) *Manager {
+	return &Manager{
+		client: cl,
+		reader: reader,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// Setup ensures that the certificates exist and writes them to the certificate directory, so that the webhook server
+// can start. It has to be called before the manager is started.
+func (m *Manager) Setup(ctx context.Context) error {
+	secret, err := m.ensure(ctx)
+	if err != nil {
+		return err
+	}
+	return m.write(secret)
+}
+
+// AddToManager registers the routines keeping the certificates up to date: all the replicas update their certificate
+// directory when the secret changes, while only the leader renews the certificates and updates the caBundles.
+func (m *Manager) AddToManager(mgr manager.Manager) error {
+	if err := mgr.Add(&syncer{m}); err != nil {
+		return fmt.Errorf("failed to add the webhook certificates synchronization: %w", err)
+	}
+	if err := mgr.Add(&rotator{m}); err != nil {
+		return fmt.Errorf("failed to add the webhook certificates rotation: %w", err)
+	}
+	return nil
+}
+
+// ensure returns the secret with valid certificates, creating or renewing them when needed. Several replicas might
+// do this at the same time: the ones losing the race use the certificates stored by the winner.
+func (m *Manager) ensure(ctx context.Context) (*corev1.Secret, error) {
+	secret, err := m.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret != nil && !m.needsRenewal(secret) {
+		return secret, nil
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.opts.SecretName,
+				Namespace: m.opts.Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "opentelemetry-operator",
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+
+	data, err := m.renew(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+	secret.Data = data
+
+	if len(secret.ResourceVersion) == 0 {
+		err = m.client.Create(ctx, secret)
+	} else {
+		err = m.client.Update(ctx, secret)
+	}
+	if k8serrors.IsAlreadyExists(err) || k8serrors.IsConflict(err) {
+		m.logger.V(1).Info("the webhook certificates have been changed by another replica")
+		return m.get(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store the webhook certificates: %w", err)
+	}
+
+	m.logger.Info("the webhook certificates have been renewed", "secret", m.opts.SecretName)
+	return secret, nil
+}
+
+func (m *Manager) get(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := m.reader.Get(ctx, types.NamespacedName{Namespace: m.opts.Namespace, Name: m.opts.SecretName}, secret)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the webhook certificates: %w", err)
+	}
+	return secret, nil
+}
+
+func (m *Manager) dnsNames() []string {
+	return []string{
+		m.opts.ServiceName,
+		fmt.Sprintf("%s.%s", m.opts.ServiceName, m.opts.Namespace),
+		fmt.Sprintf("%s.%s.svc", m.opts.ServiceName, m.opts.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", m.opts.ServiceName, m.opts.Namespace),
+	}
+}
+
+func (m *Manager) needsRenewal(secret *corev1.Secret) bool {
+	if len(secret.Data[caKey]) == 0 || certificate.NeedsRenewal(secret.Data[corev1.ServiceAccountRootCAKey], m.opts.RenewBefore, nil, nil) {
+		return true
+	}
+	return certificate.NeedsRenewal(secret.Data[corev1.TLSCertKey], m.opts.RenewBefore, m.dnsNames(), secret.Data[corev1.ServiceAccountRootCAKey])
+}
+
+// renew returns the certificates replacing the given ones. The certificate authority is only replaced when it expires
+// within the renewal period, in which case the previous one stays in the bundle, so that the clients with the
+// previous bundle keep on trusting the webhooks until the caBundles are updated.
+func (m *Manager) renew(data map[string][]byte) (map[string][]byte, error) {
+	bundle := data[corev1.ServiceAccountRootCAKey]
+	ca := certificate.KeyPair{Certificate: firstCertificate(bundle), Key: data[caKey]}
+
+	if len(ca.Key) == 0 || certificate.NeedsRenewal(ca.Certificate, m.opts.RenewBefore, nil, nil) {
+		renewed, err := certificate.NewCA("opentelemetry-operator-webhook", caValidity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the webhook certificate authority: %w", err)
+		}
+
+		bundle = renewed.Certificate
+		if cert, err := certificate.Parse(ca.Certificate); err == nil && time.Now().Before(cert.NotAfter) {
+			bundle = append(append([]byte{}, renewed.Certificate...), ca.Certificate...)
+		}
+		ca = renewed
+	}
+
+	serving, err := certificate.NewServing(ca, m.dnsNames(), m.opts.Validity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the webhook certificate: %w", err)
+	}
+
+	return map[string][]byte{
+		corev1.TLSCertKey:              serving.Certificate,
+		corev1.TLSPrivateKeyKey:        serving.Key,
+		corev1.ServiceAccountRootCAKey: bundle,
+		caKey:                          ca.Key,
+	}, nil
+}
+
+// firstCertificate returns the first certificate of the given PEM bundle.
+func firstCertificate(bundle []byte) []byte {
+	end := []byte("-----END CERTIFICATE-----")
+	if i := bytes.Index(bundle, end); i >= 0 {
+		return append(bytes.TrimSpace(bundle[:i+len(end)]), '\n')
+	}
+	return nil
+}
+
+// write stores the serving certificate in the certificate directory, where the webhook server picks up changes.
+func (m *Manager) write(secret *corev1.Secret) error {
+	if err := os.MkdirAll(m.opts.CertDir, 0700); err != nil {
+		return fmt.Errorf("failed to create the webhook certificate directory: %w", err)
+	}
+
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		path := filepath.Join(m.opts.CertDir, key)
+		if existing, err := ioutil.ReadFile(path); err == nil && bytes.Equal(existing, secret.Data[key]) {
+			continue
+		}
+
+		// the file is replaced atomically, so that the webhook server never reads a partial certificate
+		tmp := path + ".tmp"
+		if err := ioutil.WriteFile(tmp, secret.Data[key], 0600); err != nil {
+			return fmt.Errorf("failed to write the webhook certificate: %w", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to write the webhook certificate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// patchCABundles sets the certificate authorities from the secret as the caBundle of the webhook configurations and
+// of the conversion webhooks of the CRDs.
+func (m *Manager) patchCABundles(ctx context.Context, secret *corev1.Secret) error {
+	bundle := secret.Data[corev1.ServiceAccountRootCAKey]
+
+	for _, name := range m.opts.MutatingWebhookConfigurations {
+		existing := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := m.reader.Get(ctx, types.NamespacedName{Name: name}, existing); err != nil {
+			return fmt.Errorf("failed to get the mutating webhook configuration %s: %w", name, err)
+		}
+		changed := existing.DeepCopy()
+		needed := false
+		for i := range changed.Webhooks {
+			needed = needed || !bytes.Equal(changed.Webhooks[i].ClientConfig.CABundle, bundle)
+			changed.Webhooks[i].ClientConfig.CABundle = bundle
+		}
+		if err := m.patch(ctx, existing, changed, needed); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range m.opts.ValidatingWebhookConfigurations {
+		existing := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := m.reader.Get(ctx, types.NamespacedName{Name: name}, existing); err != nil {
+			return fmt.Errorf("failed to get the validating webhook configuration %s: %w", name, err)
+		}
+		changed := existing.DeepCopy()
+		needed := false
+		for i := range changed.Webhooks {
+			needed = needed || !bytes.Equal(changed.Webhooks[i].ClientConfig.CABundle, bundle)
+			changed.Webhooks[i].ClientConfig.CABundle = bundle
+		}
+		if err := m.patch(ctx, existing, changed, needed); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range m.opts.CRDs {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(crdGVK)
+		if err := m.reader.Get(ctx, types.NamespacedName{Name: name}, existing); err != nil {
+			return fmt.Errorf("failed to get the CRD %s: %w", name, err)
+		}
+		if strategy, _, _ := unstructured.NestedString(existing.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+			continue
+		}
+		encoded := base64.StdEncoding.EncodeToString(bundle)
+		current, _, _ := unstructured.NestedString(existing.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		changed := existing.DeepCopy()
+		if err := unstructured.SetNestedField(changed.Object, encoded, "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+			return fmt.Errorf("failed to set the caBundle of the CRD %s: %w", name, err)
+		}
+		if err := m.patch(ctx, existing, changed, current != encoded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) patch(ctx context.Context, existing, changed client.Object, needed bool) error {
+	if !needed {
+		return nil
+	}
+	if err := m.client.Patch(ctx, changed, client.MergeFrom(existing)); err != nil {
+		return fmt.Errorf("failed to update the caBundle of %s: %w", existing.GetName(), err)
+	}
+	m.logger.V(1).Info("updated the caBundle", "name", existing.GetName())
+	return nil
+}
+
+// syncer keeps the certificate directory of each replica in sync with the secret.
+type syncer struct {
+	*Manager
+}
+
+func (s *syncer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *syncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			secret, err := s.get(ctx)
+			if err != nil || secret == nil {
+				s.logger.Info("failed to read the webhook certificates", "error", err)
+				continue
+			}
+			if err := s.write(secret); err != nil {
+				s.logger.Error(err, "failed to update the webhook certificates")
+			}
+		}
+	}
+}
+
+// rotator renews the certificates before they expire and keeps the caBundles up to date. Only the leader runs it.
+type rotator struct {
+	*Manager
+}
+
+func (r *rotator) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		secret, err := r.ensure(ctx)
+		if err == nil {
+			err = r.patchCABundles(ctx, secret)
+		}
+		if err != nil {
+			r.logger.Error(err, "failed to rotate the webhook certificates")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
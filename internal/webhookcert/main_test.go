@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhookcert
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/certificate"
+)
+
+var logger = logf.Log.WithName("unit-tests")
+
+func newManager(t *testing.T) *Manager {
+	return New(nil, nil, logger, Options{
+		Namespace:   "observability",
+		ServiceName: "opentelemetry-operator-webhook-service",
+		CertDir:     t.TempDir(),
+		Validity:    365 * 24 * time.Hour,
+		RenewBefore: 30 * 24 * time.Hour,
+	})
+}
+
+func TestRenewNewCertificates(t *testing.T) {
+	// prepare
+	m := newManager(t)
+
+	// test
+	data, err := m.renew(nil)
+
+	// verify
+	require.NoError(t, err)
+	assert.False(t, m.needsRenewal(&corev1.Secret{Data: data}))
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(data[corev1.ServiceAccountRootCAKey]))
+	cert, err := certificate.Parse(data[corev1.TLSCertKey])
+	require.NoError(t, err)
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "opentelemetry-operator-webhook-service.observability.svc", Roots: pool})
+	assert.NoError(t, err)
+}
+
+func TestRenewKeepsTheCertificateAuthority(t *testing.T) {
+	// prepare
+	m := newManager(t)
+	data, err := m.renew(nil)
+	require.NoError(t, err)
+
+	// test
+	renewed, err := m.renew(data)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, data[corev1.ServiceAccountRootCAKey], renewed[corev1.ServiceAccountRootCAKey])
+	assert.Equal(t, data[caKey], renewed[caKey])
+	assert.NotEqual(t, data[corev1.TLSCertKey], renewed[corev1.TLSCertKey])
+}
+
+func TestRenewExpiringCertificateAuthority(t *testing.T) {
+	// prepare
+	m := newManager(t)
+	ca, err := certificate.NewCA("expiring", 24*time.Hour)
+	require.NoError(t, err)
+	data := map[string][]byte{
+		corev1.ServiceAccountRootCAKey: ca.Certificate,
+		caKey:                          ca.Key,
+	}
+	require.True(t, m.needsRenewal(&corev1.Secret{Data: data}))
+
+	// test
+	renewed, err := m.renew(data)
+
+	// verify
+	require.NoError(t, err)
+	assert.NotEqual(t, ca.Key, renewed[caKey])
+
+	// the previous authority is still trusted until the caBundles are updated
+	bundle := renewed[corev1.ServiceAccountRootCAKey]
+	assert.Contains(t, string(bundle), string(ca.Certificate))
+	assert.NotEqual(t, ca.Certificate, firstCertificate(bundle))
+
+	cert, err := certificate.Parse(renewed[corev1.TLSCertKey])
+	require.NoError(t, err)
+	caCert, err := certificate.Parse(firstCertificate(bundle))
+	require.NoError(t, err)
+	assert.NoError(t, cert.CheckSignatureFrom(caCert))
+}
+
+func TestNeedsRenewalForOtherNames(t *testing.T) {
+	// prepare
+	m := newManager(t)
+	data, err := m.renew(nil)
+	require.NoError(t, err)
+
+	// test
+	m.opts.ServiceName = "renamed-webhook-service"
+
+	// verify
+	assert.True(t, m.needsRenewal(&corev1.Secret{Data: data}))
+}
+
+func TestWriteCertificates(t *testing.T) {
+	// prepare
+	m := newManager(t)
+	secret := &corev1.Secret{Data: map[string][]byte{
+		corev1.TLSCertKey:       []byte("the-certificate"),
+		corev1.TLSPrivateKeyKey: []byte("the-key"),
+	}}
+
+	// test
+	err := m.write(secret)
+
+	// verify
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(m.opts.CertDir, corev1.TLSCertKey))
+	require.NoError(t, err)
+	assert.Equal(t, "the-certificate", string(content))
+	content, err = ioutil.ReadFile(filepath.Join(m.opts.CertDir, corev1.TLSPrivateKeyKey))
+	require.NoError(t, err)
+	assert.Equal(t, "the-key", string(content))
+}
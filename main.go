@@ -16,32 +16,48 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	componentconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
 	opentelemetryiov1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/controllers"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/internal/podinjector"
 	"github.com/open-telemetry/opentelemetry-operator/internal/version"
+	"github.com/open-telemetry/opentelemetry-operator/internal/webhookcert"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/autodetect"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/distribution"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/upgrade"
 	// +kubebuilder:scaffold:imports
 )
 
+const (
+	// configReloadInterval is how often the configuration file is checked for changes
+	configReloadInterval = 10 * time.Second
+)
+
 var (
 	scheme   = k8sruntime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -51,6 +67,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(opentelemetryiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(configv1alpha1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -60,13 +77,19 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
 
-	// Add flags related to this operator
+	// Add flags related to this operator, the ones set explicitly take precedence over the configuration file
+	var configFile string
 	var metricsAddr string
 	var enableLeaderElection bool
+	var webhookCertificates string
+	pflag.StringVar(&configFile, "config", "",
+		"The path to the operator's configuration file, an OperatorConfiguration of the config.opentelemetry.io/v1alpha1 API version.")
 	pflag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	pflag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	pflag.StringVar(&webhookCertificates, "webhook-certificates", string(configv1alpha1.WebhookCertificatesCertManager),
+		"Who provides the certificates of the webhooks: cert-manager, or the operator itself (self-managed).")
 
 	// Add flags related to this operator
 	v := version.Get()
@@ -91,35 +114,95 @@ func main() {
 		os.Exit(1)
 	}
 
+	// the instances are reconciled again once the capabilities of the cluster change, the reconciler is set below,
+	// before the auto-detection starts
+	var reconciler *controllers.OpenTelemetryCollectorReconciler
 	cfg := config.New(
 		config.WithLogger(ctrl.Log.WithName("config")),
 		config.WithVersion(v),
 		config.WithAutoDetect(ad),
+		config.WithOnChange(func() error {
+			if reconciler == nil {
+				return nil
+			}
+			return reconciler.Refresh(context.Background())
+		}),
 	)
 
 	pflag.CommandLine.AddFlagSet(cfg.FlagSet())
 
 	pflag.Parse()
 
-	watchNamespace, found := os.LookupEnv("WATCH_NAMESPACE")
-	if found {
-		setupLog.Info("watching namespace(s)", "namespaces", watchNamespace)
-	} else {
-		setupLog.Info("the env var WATCH_NAMESPACE isn't set, watching all namespaces")
+	// the configuration file holds the settings that used to be set via flags and env vars, which are still
+	// supported for compatibility
+	file := &configv1alpha1.OperatorConfiguration{}
+	if len(configFile) > 0 {
+		file, err = config.Load(configFile)
+		if err != nil {
+			setupLog.Error(err, "failed to load the configuration file", "path", configFile)
+			os.Exit(1)
+		}
+		cfg.Apply(file)
+	}
+
+	watchNamespaces := file.WatchNamespaces
+	if value := os.Getenv("WATCH_NAMESPACE"); len(value) > 0 && len(watchNamespaces) == 0 && len(file.CacheNamespace) == 0 {
+		if file.NamespaceSelector != nil {
+			// the namespaces have to be watched cluster-wide to be selected
+			setupLog.Info("the env var WATCH_NAMESPACE is ignored, as the configuration file has a namespace selector")
+		} else {
+			watchNamespaces = strings.Split(value, ",")
+		}
+	}
+	if len(watchNamespaces) > 0 {
+		setupLog.Info("watching namespace(s)", "namespaces", strings.Join(watchNamespaces, ","))
+	} else if len(file.CacheNamespace) == 0 {
+		setupLog.Info("no namespaces are set, watching all namespaces")
+	}
+
+	webhooksEnabled := configv1alpha1.Enabled(file.Features.Webhooks)
+	if file.Features.Webhooks == nil && os.Getenv("ENABLE_WEBHOOKS") == "false" {
+		webhooksEnabled = false
+	}
+
+	if !pflag.CommandLine.Changed("webhook-certificates") && len(file.WebhookCertificates) > 0 {
+		webhookCertificates = string(file.WebhookCertificates)
 	}
 
 	mgrOptions := ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsAddr,
-		Port:               9443,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "9f7554c3.opentelemetry.io",
-		Namespace:          watchNamespace,
+		Scheme:         scheme,
+		LeaderElection: enableLeaderElection,
+	}
+	if pflag.CommandLine.Changed("metrics-addr") {
+		mgrOptions.MetricsBindAddress = metricsAddr
+	}
+	if len(watchNamespaces) == 1 {
+		mgrOptions.Namespace = watchNamespaces[0]
+	} else if len(watchNamespaces) > 1 {
+		mgrOptions.NewCache = cache.MultiNamespacedCacheBuilder(watchNamespaces)
+	}
+	managerFile := file.DeepCopy()
+	if managerFile.LeaderElection == nil {
+		// the manager expects the leader election settings to be set
+		managerFile.LeaderElection = &componentconfigv1alpha1.LeaderElectionConfiguration{}
+	}
+	if mgrOptions, err = mgrOptions.AndFrom(managerFile); err != nil {
+		setupLog.Error(err, "failed to apply the configuration file to the manager")
+		os.Exit(1)
 	}
 
-	if strings.Contains(watchNamespace, ",") {
-		mgrOptions.Namespace = ""
-		mgrOptions.NewCache = cache.MultiNamespacedCacheBuilder(strings.Split(watchNamespace, ","))
+	// the defaults for the settings that neither the flags nor the configuration file have set
+	if len(mgrOptions.MetricsBindAddress) == 0 {
+		mgrOptions.MetricsBindAddress = metricsAddr
+	}
+	if mgrOptions.Port == 0 {
+		mgrOptions.Port = 9443
+	}
+	if len(mgrOptions.LeaderElectionID) == 0 {
+		mgrOptions.LeaderElectionID = "9f7554c3.opentelemetry.io"
+	}
+	if len(mgrOptions.CertDir) == 0 {
+		mgrOptions.CertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
 	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
@@ -128,6 +211,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// reload the configuration file when it changes
+	if len(configFile) > 0 {
+		err = mgr.Add(config.NewFileWatcher(cfg, ctrl.Log.WithName("config"), configFile, file, configReloadInterval))
+		if err != nil {
+			setupLog.Error(err, "failed to start the configuration file watcher")
+		}
+	}
+
 	// run the auto-detect mechanism for the configuration
 	err = mgr.Add(manager.RunnableFunc(func(_ context.Context) error {
 		return cfg.StartAutoDetect()
@@ -137,33 +228,96 @@ func main() {
 	}
 
 	// adds the upgrade mechanism to be executed once the manager is ready
-	err = mgr.Add(manager.RunnableFunc(func(c context.Context) error {
-		return upgrade.ManagedInstances(c, ctrl.Log.WithName("upgrade"), v, mgr.GetClient())
-	}))
-	if err != nil {
-		setupLog.Error(err, "failed to upgrade managed instances")
+	if configv1alpha1.Enabled(file.Features.Upgrades) {
+		err = mgr.Add(manager.RunnableFunc(func(c context.Context) error {
+			return upgrade.ManagedInstances(c, ctrl.Log.WithName("upgrade"), v, cfg.CollectorImageForMode, namespaceInScope(cfg, mgr.GetClient()), mgr.GetClient())
+		}))
+		if err != nil {
+			setupLog.Error(err, "failed to upgrade managed instances")
+		}
+	}
+
+	// the capabilities are detected before setting up the controller, which only watches the APIs served by the cluster
+	if err := cfg.AutoDetect(); err != nil {
+		setupLog.Error(err, "failed to auto-detect the capabilities of the cluster")
 	}
 
-	if err = controllers.NewReconciler(controllers.Params{
+	reconciler = controllers.NewReconciler(controllers.Params{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("OpenTelemetryCollector"),
 		Scheme:   mgr.GetScheme(),
 		Config:   cfg,
 		Recorder: mgr.GetEventRecorderFor("opentelemetry-operator"),
-	}).SetupWithManager(mgr); err != nil {
+	})
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenTelemetryCollector")
 		os.Exit(1)
 	}
 
-	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = (&opentelemetryiov1alpha1.OpenTelemetryCollector{}).SetupWebhookWithManager(mgr); err != nil {
+	if webhooksEnabled {
+		switch configv1alpha1.WebhookCertificates(webhookCertificates) {
+		case configv1alpha1.WebhookCertificatesCertManager:
+			// the certificates are mounted from the secret issued by cert-manager
+		case configv1alpha1.WebhookCertificatesSelfManaged:
+			if err := setupWebhookCertificates(mgr, mgrOptions.CertDir); err != nil {
+				setupLog.Error(err, "failed to set up the webhook certificates")
+				os.Exit(1)
+			}
+		default:
+			setupLog.Error(fmt.Errorf("unknown value %q", webhookCertificates), "invalid flag", "flag", "webhook-certificates")
+			os.Exit(1)
+		}
+
+		distributions, err := distribution.Load(cfg.CollectorDistributions())
+		if err != nil {
+			setupLog.Error(err, "failed to load the OpenTelemetry Collector distributions")
+			os.Exit(1)
+		}
+
+		webhookOpts := opentelemetryiov1alpha1.WebhookOptions{
+			CollectorImage: cfg.CollectorImageForMode,
+			Distribution:   distributions.ForImage,
+			InScope:        namespaceInScope(cfg, mgr.GetClient()),
+			Validate: func(otelcol *opentelemetryiov1alpha1.OpenTelemetryCollector) error {
+				if err := distributions.Validate(otelcol); err != nil {
+					return err
+				}
+				if err := collector.ValidateContainers(*otelcol); err != nil {
+					return err
+				}
+				if err := collector.ValidateHostPorts(ctrl.Log.WithName("webhook"), *otelcol); err != nil {
+					return err
+				}
+				return collector.ValidatePodTemplateOverlay(cfg, ctrl.Log.WithName("webhook"), *otelcol)
+			},
+			Warnings: func(otelcol *opentelemetryiov1alpha1.OpenTelemetryCollector) []string {
+				_, _, err := collector.Probes(*otelcol)
+				switch {
+				case errors.Is(err, collector.ErrSidecarProbes):
+					if otelcol.Spec.LivenessProbe != nil || otelcol.Spec.ReadinessProbe != nil {
+						return []string{fmt.Sprintf("the liveness and readiness probe settings are ignored: %s", err)}
+					}
+				case err != nil:
+					return []string{fmt.Sprintf("the collector container won't have liveness and readiness probes: %s", err)}
+				}
+				return nil
+			},
+		}
+		if err = (&opentelemetryiov1alpha1.OpenTelemetryCollector{}).SetupWebhookWithManager(mgr, webhookOpts); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "OpenTelemetryCollector")
 			os.Exit(1)
 		}
 
-		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: podinjector.NewPodSidecarInjector(cfg, ctrl.Log.WithName("sidecar"), mgr.GetClient()),
-		})
+		if err = podinjector.RegisterIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+			setupLog.Error(err, "unable to register the field indexes", "webhook", "Pod")
+			os.Exit(1)
+		}
+
+		if configv1alpha1.Enabled(file.Features.SidecarInjection) {
+			mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
+				Handler: podinjector.NewPodSidecarInjector(cfg, ctrl.Log.WithName("sidecar"), mgr.GetClient(), mgr.GetEventRecorderFor("opentelemetry-operator")),
+			})
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -173,3 +327,51 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// namespaceInScope returns a function telling whether the instances of a namespace are managed by the operator. The
+// namespaces that can't be retrieved are considered in scope, so that their instances are still validated.
+func namespaceInScope(cfg config.Config, reader client.Reader) func(namespace string) bool {
+	logger := ctrl.Log.WithName("namespace-selector")
+	return func(namespace string) bool {
+		if len(namespace) == 0 {
+			return true
+		}
+		inScope, err := cfg.NamespaceInScope(context.Background(), reader, namespace)
+		if err != nil {
+			logger.Error(err, "failed to determine whether the namespace is managed by the operator", "namespace", namespace)
+			return true
+		}
+		return inScope
+	}
+}
+
+// setupWebhookCertificates makes sure that the webhook server has a certificate before it starts, and registers the
+// routines renewing it with the manager.
+func setupWebhookCertificates(mgr manager.Manager, certDir string) error {
+	namespace, found := os.LookupEnv("POD_NAMESPACE")
+	if !found {
+		content, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+		if err != nil {
+			return fmt.Errorf("failed to determine the operator's namespace, set the POD_NAMESPACE env var: %w", err)
+		}
+		namespace = strings.TrimSpace(string(content))
+	}
+
+	certs := webhookcert.New(mgr.GetClient(), mgr.GetAPIReader(), ctrl.Log.WithName("webhook-certificates"), webhookcert.Options{
+		Namespace:                       namespace,
+		SecretName:                      "opentelemetry-operator-controller-manager-service-cert",
+		ServiceName:                     "opentelemetry-operator-webhook-service",
+		CertDir:                         certDir,
+		MutatingWebhookConfigurations:   []string{"opentelemetry-operator-mutating-webhook-configuration"},
+		ValidatingWebhookConfigurations: []string{"opentelemetry-operator-validating-webhook-configuration"},
+		CRDs:                            []string{"opentelemetrycollectors.opentelemetry.io"},
+		Validity:                        365 * 24 * time.Hour,
+		RenewBefore:                     30 * 24 * time.Hour,
+		CheckInterval:                   10 * time.Minute,
+	})
+
+	if err := certs.Setup(context.Background()); err != nil {
+		return err
+	}
+	return certs.AddToManager(mgr)
+}
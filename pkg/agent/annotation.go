@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agent injects the endpoints of the collectors running as node agents into the application pods.
+package agent
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Annotation contains the annotation name that pods contain, indicating whether the endpoint of the agent on their
+	// node should be injected. The value is true, the name of a daemonset instance from the pod's namespace, or
+	// <namespace>/<name> for an instance from another namespace.
+	Annotation = "agent.opentelemetry.io/inject"
+)
+
+// AnnotationValue returns the effective annotation value: the pod's annotation when set, the namespace's otherwise.
+func AnnotationValue(ns corev1.Namespace, pod corev1.Pod) string {
+	if value := pod.Annotations[Annotation]; len(value) > 0 {
+		return value
+	}
+	return ns.Annotations[Annotation]
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+const (
+	// EnvNodeIP is the environment variable holding the IP of the pod's node, where the agent is exposed.
+	EnvNodeIP = "OTEL_NODE_IP"
+
+	// EnvOTLPEndpoint is the environment variable read by the OpenTelemetry SDKs for the endpoint of their OTLP
+	// exporters.
+	EnvOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	// EnvOTLPProtocol is the environment variable read by the OpenTelemetry SDKs for the protocol of their OTLP
+	// exporters.
+	EnvOTLPProtocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
+
+	label = "agent.opentelemetry.io/injected"
+)
+
+// Add injects into the pod's containers the environment variables pointing to the given agent on the pod's node: the
+// node's IP and, when the agent exposes the OTLP receiver on the nodes, the OTLP endpoint. The variables already set
+// by the containers are left as they are.
+func Add(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector, pod corev1.Pod) corev1.Pod {
+	// the node's IP comes first, as the endpoint refers to it
+	nodeIP := corev1.EnvVar{
+		Name: EnvNodeIP,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.hostIP"},
+		},
+	}
+	otlp := otlpEnv(collector.ContainerPorts(logger, otelcol))
+	if len(otlp) == 0 {
+		logger.V(1).Info("the agent doesn't expose the OTLP receiver on the nodes, only the node's IP is injected", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
+	}
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		if !hasEnv(*container, EnvNodeIP) {
+			container.Env = append(container.Env, nodeIP)
+		}
+		// the protocol only makes sense along with the endpoint
+		if !hasEnv(*container, EnvOTLPEndpoint) {
+			container.Env = append(container.Env, otlp...)
+		}
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[label] = fmt.Sprintf("%s.%s", otelcol.Namespace, otelcol.Name)
+
+	return pod
+}
+
+// otlpEnv returns the environment variables for the OTLP exporters, preferring the gRPC protocol.
+func otlpEnv(ports []corev1.ContainerPort) []corev1.EnvVar {
+	hostPorts := map[string]int32{}
+	for _, port := range ports {
+		if port.HostPort > 0 {
+			hostPorts[port.Name] = port.HostPort
+		}
+	}
+
+	if port, ok := hostPorts["otlp-grpc"]; ok {
+		return []corev1.EnvVar{
+			{Name: EnvOTLPEndpoint, Value: fmt.Sprintf("http://$(%s):%d", EnvNodeIP, port)},
+			{Name: EnvOTLPProtocol, Value: "grpc"},
+		}
+	}
+	if port, ok := hostPorts["otlp-http"]; ok {
+		return []corev1.EnvVar{
+			{Name: EnvOTLPEndpoint, Value: fmt.Sprintf("http://$(%s):%d", EnvNodeIP, port)},
+			{Name: EnvOTLPProtocol, Value: "http/protobuf"},
+		}
+	}
+	return nil
+}
+
+func hasEnv(container corev1.Container, name string) bool {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/agent"
+)
+
+var logger = logf.Log.WithName("unit-tests")
+
+var nodeIP = corev1.EnvVar{
+	Name: "OTEL_NODE_IP",
+	ValueFrom: &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.hostIP"},
+	},
+}
+
+func agentInstance(hostPorts ...v1alpha1.HostPortSpec) v1alpha1.OpenTelemetryCollector {
+	return v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "agent",
+			Namespace: "observability",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode:  v1alpha1.ModeDaemonSet,
+			Image: "otel/opentelemetry-collector:0.31.0",
+			Config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+      http:
+`,
+			HostPorts: hostPorts,
+		},
+	}
+}
+
+func TestAddWithOTLPGRPC(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "my-app"}},
+		},
+	}
+	otelcol := agentInstance(v1alpha1.HostPortSpec{Name: "otlp-http"}, v1alpha1.HostPortSpec{Name: "otlp-grpc", HostPort: 14317})
+
+	// test
+	changed := agent.Add(logger, otelcol, pod)
+
+	// verify
+	assert.Equal(t, []corev1.EnvVar{
+		nodeIP,
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://$(OTEL_NODE_IP):14317"},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "grpc"},
+	}, changed.Spec.Containers[0].Env)
+	assert.Equal(t, "observability.agent", changed.Labels["agent.opentelemetry.io/injected"])
+}
+
+func TestAddWithOTLPHTTP(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "my-app"}},
+		},
+	}
+	otelcol := agentInstance(v1alpha1.HostPortSpec{Name: "otlp-http"})
+
+	// test
+	changed := agent.Add(logger, otelcol, pod)
+
+	// verify
+	assert.Equal(t, []corev1.EnvVar{
+		nodeIP,
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://$(OTEL_NODE_IP):4318"},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+	}, changed.Spec.Containers[0].Env)
+}
+
+func TestAddWithoutHostPorts(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "my-app"}},
+		},
+	}
+
+	// test
+	changed := agent.Add(logger, agentInstance(), pod)
+
+	// verify
+	assert.Equal(t, []corev1.EnvVar{nodeIP}, changed.Spec.Containers[0].Env)
+}
+
+func TestAddKeepsTheExistingEnvVars(t *testing.T) {
+	// prepare
+	existing := []corev1.EnvVar{{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://collector:4317"}}
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "my-app", Env: existing}},
+		},
+	}
+
+	// test
+	changed := agent.Add(logger, agentInstance(v1alpha1.HostPortSpec{Name: "otlp-grpc"}), pod)
+
+	// verify
+	assert.Equal(t, append(existing, nodeIP), changed.Spec.Containers[0].Env)
+}
+
+func TestAnnotationValue(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		ns       string
+		pod      string
+		expected string
+	}{
+		{"none", "", "", ""},
+		{"namespace only", "observability/agent", "", "observability/agent"},
+		{"pod only", "", "true", "true"},
+		{"pod overrides the namespace", "observability/agent", "false", "false"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if len(tt.ns) > 0 {
+				ns.Annotations[agent.Annotation] = tt.ns
+			}
+			if len(tt.pod) > 0 {
+				pod.Annotations[agent.Annotation] = tt.pod
+			}
+
+			// test
+			value := agent.AnnotationValue(ns, pod)
+
+			// verify
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
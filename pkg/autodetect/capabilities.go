@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autodetect
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// the group versions are in the order of preference, the first one served by the cluster is used
+	hpaGroupVersions = []string{"autoscaling/v2", "autoscaling/v2beta2", "autoscaling/v2beta1", "autoscaling/v1"}
+	pdbGroupVersions = []string{"policy/v1", "policy/v1beta1"}
+)
+
+// Capabilities holds the traits of the cluster that the reconciliation of the instances depends on.
+type Capabilities struct {
+	// ServerVersion is the version of the Kubernetes API server, like v1.21.2.
+	ServerVersion string
+
+	// HPAVersion is the preferred group version for horizontal pod autoscalers, empty when they aren't served.
+	HPAVersion string
+
+	// PDBVersion is the preferred group version for pod disruption budgets, empty when they aren't served.
+	PDBVersion string
+
+	// PrometheusOperator is whether the Prometheus Operator's API (monitoring.coreos.com) is available.
+	PrometheusOperator bool
+
+	// CertManager is whether cert-manager's API (cert-manager.io) is available.
+	CertManager bool
+
+	// Istio is whether Istio's networking API (networking.istio.io) is available.
+	Istio bool
+
+	// GatewayAPI is whether the Gateway API (gateway.networking.k8s.io) is available.
+	GatewayAPI bool
+
+	// VPA is whether the vertical pod autoscaler's API (autoscaling.k8s.io) is available.
+	VPA bool
+
+	// OpenShiftRoutes is whether OpenShift's route API (route.openshift.io) is available.
+	OpenShiftRoutes bool
+}
+
+// capabilitiesFor determines the capabilities of a cluster with the given version, serving the given API groups.
+func capabilitiesFor(serverVersion string, groups []metav1.APIGroup) Capabilities {
+	groupVersions := map[string]bool{}
+	for _, group := range groups {
+		groupVersions[group.Name] = true
+		for _, v := range group.Versions {
+			groupVersions[v.GroupVersion] = true
+		}
+	}
+
+	return Capabilities{
+		ServerVersion:      serverVersion,
+		HPAVersion:         preferred(groupVersions, hpaGroupVersions),
+		PDBVersion:         preferred(groupVersions, pdbGroupVersions),
+		PrometheusOperator: groupVersions["monitoring.coreos.com"],
+		CertManager:        groupVersions["cert-manager.io"],
+		Istio:              groupVersions["networking.istio.io"],
+		GatewayAPI:         groupVersions["gateway.networking.k8s.io"],
+		VPA:                groupVersions["autoscaling.k8s.io"],
+		OpenShiftRoutes:    groupVersions["route.openshift.io"],
+	}
+}
+
+func preferred(served map[string]bool, candidates []string) string {
+	for _, gv := range candidates {
+		if served[gv] {
+			return gv
+		}
+	}
+	return ""
+}
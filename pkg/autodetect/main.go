@@ -27,6 +27,7 @@ var _ AutoDetect = (*autoDetect)(nil)
 // AutoDetect provides an assortment of routines that auto-detect traits based on the runtime.
 type AutoDetect interface {
 	Platform() (platform.Platform, error)
+	Capabilities() (Capabilities, error)
 }
 
 type autoDetect struct {
@@ -64,3 +65,18 @@ func (a *autoDetect) Platform() (platform.Platform, error) {
 
 	return platform.Kubernetes, nil
 }
+
+// Capabilities returns the capabilities of the cluster, based on its version and on the API groups it serves.
+func (a *autoDetect) Capabilities() (Capabilities, error) {
+	info, err := a.dcl.ServerVersion()
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	apiList, err := a.dcl.ServerGroups()
+	if err != nil {
+		return Capabilities{}, err
+	}
+
+	return capabilitiesFor(info.GitVersion, apiList.Groups), nil
+}
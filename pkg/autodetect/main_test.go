@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/rest"
 
 	"github.com/open-telemetry/opentelemetry-operator/pkg/autodetect"
@@ -88,3 +89,67 @@ func TestUnknownPlatformOnError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, platform.Unknown, plt)
 }
+
+func TestDetectCapabilities(t *testing.T) {
+	// prepare
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var output interface{}
+		switch req.URL.Path {
+		case "/version":
+			output = version.Info{GitVersion: "v1.21.2"}
+		case "/api":
+			output = metav1.APIVersions{Versions: []string{"v1"}}
+		default:
+			output = metav1.APIGroupList{
+				Groups: []metav1.APIGroup{
+					{Name: "autoscaling", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "autoscaling/v1"}, {GroupVersion: "autoscaling/v2beta2"}}},
+					{Name: "policy", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "policy/v1beta1"}}},
+					{Name: "monitoring.coreos.com"},
+					{Name: "cert-manager.io"},
+					{Name: "gateway.networking.k8s.io"},
+					{Name: "route.openshift.io"},
+				},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		require.NoError(t, json.NewEncoder(w).Encode(output))
+	}))
+	defer server.Close()
+
+	autoDetect, err := autodetect.New(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	// test
+	capabilities, err := autoDetect.Capabilities()
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, autodetect.Capabilities{
+		ServerVersion:      "v1.21.2",
+		HPAVersion:         "autoscaling/v2beta2",
+		PDBVersion:         "policy/v1beta1",
+		PrometheusOperator: true,
+		CertManager:        true,
+		GatewayAPI:         true,
+		OpenShiftRoutes:    true,
+	}, capabilities)
+}
+
+func TestCapabilitiesOnError(t *testing.T) {
+	// prepare
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	autoDetect, err := autodetect.New(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	// test
+	_, err = autoDetect.Capabilities()
+
+	// verify
+	assert.Error(t, err)
+}
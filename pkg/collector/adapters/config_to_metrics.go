@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapters
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// DefaultMetricsPort is the port of the collector's own metrics when the configuration doesn't set their address.
+const DefaultMetricsPort int32 = 8888
+
+// ConfigToMetricsPort returns the port the collector exposes its own metrics on, from the address in the service's
+// telemetry settings of the incoming configuration object.
+func ConfigToMetricsPort(config map[interface{}]interface{}) (int32, error) {
+	// the address defaults to 0.0.0.0:8888:
+	// ```yaml
+	// service:
+	//   telemetry:
+	//     metrics:
+	//       address: 0.0.0.0:8888
+	// ```
+	service, _ := config["service"].(map[interface{}]interface{})
+	telemetry, _ := service["telemetry"].(map[interface{}]interface{})
+	metrics, _ := telemetry["metrics"].(map[interface{}]interface{})
+
+	address, ok := metrics["address"].(string)
+	if !ok || len(address) == 0 {
+		return DefaultMetricsPort, nil
+	}
+
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q for the collector's metrics: %w", address, err)
+	}
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port in the address %q of the collector's metrics: %w", address, err)
+	}
+	return int32(port), nil
+}
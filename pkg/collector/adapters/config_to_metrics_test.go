@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
+)
+
+func TestConfigToMetricsPort(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   string
+		expected int32
+	}{
+		{"default", "receivers:\n  otlp:\n", 8888},
+		{"other telemetry settings", "service:\n  telemetry:\n    metrics:\n      level: detailed\n", 8888},
+		{"address", "service:\n  telemetry:\n    metrics:\n      address: 0.0.0.0:9090\n", 9090},
+		{"port only", "service:\n  telemetry:\n    metrics:\n      address: :9091\n", 9091},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			config, err := adapters.ConfigFromString(tt.config)
+			require.NoError(t, err)
+
+			// test
+			port, err := adapters.ConfigToMetricsPort(config)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, port)
+		})
+	}
+}
+
+func TestConfigToMetricsPortWithInvalidAddress(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		config string
+	}{
+		{"without port", "service:\n  telemetry:\n    metrics:\n      address: localhost\n"},
+		{"invalid port", "service:\n  telemetry:\n    metrics:\n      address: localhost:metrics\n"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			config, err := adapters.ConfigFromString(tt.config)
+			require.NoError(t, err)
+
+			// test
+			_, err = adapters.ConfigToMetricsPort(config)
+
+			// verify
+			assert.Error(t, err)
+		})
+	}
+}
@@ -17,6 +17,7 @@ package adapters
 import (
 	"errors"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 
@@ -33,6 +34,13 @@ var (
 
 // ConfigToReceiverPorts converts the incoming configuration object into a set of service ports required by the receivers.
 func ConfigToReceiverPorts(logger logr.Logger, config map[interface{}]interface{}) ([]corev1.ServicePort, error) {
+	return ConfigToReceiverPortsForVersion(logger, config, nil)
+}
+
+// ConfigToReceiverPortsForVersion converts the incoming configuration object into a set of service ports required by
+// the receivers, using the default ports of the given OpenTelemetry Collector version for the receivers without an
+// explicit endpoint.
+func ConfigToReceiverPortsForVersion(logger logr.Logger, config map[interface{}]interface{}, version *semver.Version) ([]corev1.ServicePort, error) {
 	// now, we gather which ports we might need to open
 	// for that, we get all the receivers and check their `endpoint` properties,
 	// extracting the port from it. The port name has to be a "DNS_LABEL", so, we try to make it follow the pattern:
@@ -66,7 +74,7 @@ func ConfigToReceiverPorts(logger logr.Logger, config map[interface{}]interface{
 		}
 
 		rcvrName := key.(string)
-		rcvrParser := parser.For(logger, rcvrName, receiver)
+		rcvrParser := parser.ForVersion(logger, rcvrName, version, receiver)
 
 		rcvrPorts, err := rcvrParser.Ports()
 		if err != nil {
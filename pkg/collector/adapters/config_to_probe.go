@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	healthCheckExtension   = "health_check"
+	defaultHealthCheckPort = 13133
+	defaultHealthCheckPath = "/"
+)
+
+var (
+	// ErrNoHealthCheck indicates that the health_check extension isn't part of the configuration.
+	ErrNoHealthCheck = errors.New("the health_check extension isn't configured")
+
+	// ErrHealthCheckNotEnabled indicates that the health_check extension is configured, but not listed in the
+	// service's extensions.
+	ErrHealthCheckNotEnabled = errors.New("the health_check extension isn't enabled in service.extensions")
+)
+
+// ConfigToContainerProbe converts the health_check extension of the incoming configuration object into a probe
+// checking the extension's endpoint.
+func ConfigToContainerProbe(config map[interface{}]interface{}) (*corev1.Probe, error) {
+	// the extension can be named, like health_check/internal, and has to be enabled in the service:
+	// ```yaml
+	// extensions:
+	//   health_check:
+	//     endpoint: 0.0.0.0:13133
+	//     path: /
+	// service:
+	//   extensions: [health_check]
+	// ```
+	extensions, _ := config["extensions"].(map[interface{}]interface{})
+
+	name, enabled := enabledHealthCheck(config)
+	if !enabled {
+		for key := range extensions {
+			if isHealthCheck(key) {
+				return nil, ErrHealthCheckNotEnabled
+			}
+		}
+		return nil, ErrNoHealthCheck
+	}
+
+	extension, found := extensions[name]
+	if !found {
+		return nil, ErrNoHealthCheck
+	}
+	settings, _ := extension.(map[interface{}]interface{})
+
+	port, err := healthCheckPort(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	path := defaultHealthCheckPath
+	if p, ok := settings["path"].(string); ok && len(p) > 0 {
+		path = p
+	}
+
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: path,
+				Port: intstr.FromInt(port),
+			},
+		},
+	}, nil
+}
+
+// enabledHealthCheck returns the name of the first health_check extension listed in the service's extensions.
+func enabledHealthCheck(config map[interface{}]interface{}) (interface{}, bool) {
+	service, _ := config["service"].(map[interface{}]interface{})
+	enabled, _ := service["extensions"].([]interface{})
+	for _, name := range enabled {
+		if isHealthCheck(name) {
+			return name, true
+		}
+	}
+	return nil, false
+}
+
+func isHealthCheck(name interface{}) bool {
+	s, ok := name.(string)
+	return ok && (s == healthCheckExtension || strings.HasPrefix(s, healthCheckExtension+"/"))
+}
+
+// healthCheckPort returns the port of the extension's endpoint, or of its port property used by older versions.
+func healthCheckPort(settings map[interface{}]interface{}) (int, error) {
+	if endpoint, ok := settings["endpoint"].(string); ok && len(endpoint) > 0 {
+		_, portStr, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			return 0, fmt.Errorf("invalid endpoint %q for the health_check extension: %w", endpoint, err)
+		}
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid port in the endpoint %q of the health_check extension: %w", endpoint, err)
+		}
+		return port, nil
+	}
+
+	if port, ok := settings["port"].(int); ok {
+		return port, nil
+	}
+
+	return defaultHealthCheckPort, nil
+}
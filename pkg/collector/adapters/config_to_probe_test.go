@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
+)
+
+func TestConfigToContainerProbe(t *testing.T) {
+	for _, tt := range []struct {
+		desc         string
+		config       string
+		expectedPort int
+		expectedPath string
+	}{
+		{
+			"defaults",
+			"extensions:\n  health_check:\nservice:\n  extensions: [health_check]\n",
+			13133, "/",
+		},
+		{
+			"endpoint and path",
+			"extensions:\n  health_check:\n    endpoint: 0.0.0.0:8080\n    path: /health\nservice:\n  extensions: [health_check]\n",
+			8080, "/health",
+		},
+		{
+			"named extension",
+			"extensions:\n  health_check/internal:\n    endpoint: :8081\nservice:\n  extensions: [zpages, health_check/internal]\n",
+			8081, "/",
+		},
+		{
+			"legacy port",
+			"extensions:\n  health_check:\n    port: 8082\nservice:\n  extensions: [health_check]\n",
+			8082, "/",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			config, err := adapters.ConfigFromString(tt.config)
+			require.NoError(t, err)
+
+			// test
+			probe, err := adapters.ConfigToContainerProbe(config)
+
+			// verify
+			require.NoError(t, err)
+			require.NotNil(t, probe.HTTPGet)
+			assert.Equal(t, &corev1.HTTPGetAction{Path: tt.expectedPath, Port: intstr.FromInt(tt.expectedPort)}, probe.HTTPGet)
+		})
+	}
+}
+
+func TestConfigToContainerProbeWithoutHealthCheck(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   string
+		expected error
+	}{
+		{"no extensions", "receivers:\n  otlp:\n", adapters.ErrNoHealthCheck},
+		{"other extensions", "extensions:\n  zpages:\nservice:\n  extensions: [zpages]\n", adapters.ErrNoHealthCheck},
+		{"not enabled", "extensions:\n  health_check:\nservice:\n  extensions: [zpages]\n", adapters.ErrHealthCheckNotEnabled},
+		{"enabled but not configured", "service:\n  extensions: [health_check]\n", adapters.ErrNoHealthCheck},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			config, err := adapters.ConfigFromString(tt.config)
+			require.NoError(t, err)
+
+			// test
+			probe, err := adapters.ConfigToContainerProbe(config)
+
+			// verify
+			assert.Nil(t, probe)
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestConfigToContainerProbeWithInvalidEndpoint(t *testing.T) {
+	// prepare
+	config, err := adapters.ConfigFromString("extensions:\n  health_check:\n    endpoint: localhost\nservice:\n  extensions: [health_check]\n")
+	require.NoError(t, err)
+
+	// test
+	_, err = adapters.ConfigToContainerProbe(config)
+
+	// verify
+	assert.Error(t, err)
+}
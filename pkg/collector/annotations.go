@@ -17,6 +17,7 @@ package collector
 import (
 	"crypto/sha256"
 	"fmt"
+	"strconv"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 )
@@ -28,7 +29,7 @@ func Annotations(instance v1alpha1.OpenTelemetryCollector) map[string]string {
 
 	// set default prometheus annotations
 	annotations["prometheus.io/scrape"] = "true"
-	annotations["prometheus.io/port"] = "8888"
+	annotations["prometheus.io/port"] = strconv.Itoa(int(MetricsPort(instance)))
 	annotations["prometheus.io/path"] = "/metrics"
 
 	// allow override of prometheus annotations
@@ -38,7 +39,12 @@ func Annotations(instance v1alpha1.OpenTelemetryCollector) map[string]string {
 		}
 	}
 	// make sure sha256 for configMap is always calculated
-	annotations["opentelemetry-operator-config/sha256"] = getConfigMapSHA(instance.Spec.Config)
+	annotations["opentelemetry-operator-config/sha256"] = getConfigMapSHA(Config(instance))
+
+	// restart the pods when the receivers' certificate is renewed
+	if instance.Spec.TLS != nil && instance.Status.TLS != nil && len(instance.Status.TLS.Fingerprint) > 0 {
+		annotations[AnnotationTLSFingerprint] = instance.Status.TLS.Fingerprint
+	}
 
 	return annotations
 }
@@ -45,6 +45,21 @@ func TestDefaultAnnotations(t *testing.T) {
 	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", annotations["opentelemetry-operator-config/sha256"])
 }
 
+func TestAnnotationsWithMetricsAddress(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "service:\n  telemetry:\n    metrics:\n      address: 0.0.0.0:8889\n",
+		},
+	}
+
+	// test
+	annotations := Annotations(otelcol)
+
+	// verify
+	assert.Equal(t, "8889", annotations["prometheus.io/port"])
+}
+
 func TestUserAnnotations(t *testing.T) {
 	// prepare
 	otelcol := v1alpha1.OpenTelemetryCollector{
@@ -87,3 +102,24 @@ func TestAnnotationsPropagateDown(t *testing.T) {
 	assert.Len(t, annotations, 5)
 	assert.Equal(t, "mycomponent", annotations["myapp"])
 }
+
+func TestAnnotationsWithTLS(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "receivers:\n  zipkin:\n",
+			TLS:    &v1alpha1.TLSSpec{},
+		},
+		Status: v1alpha1.OpenTelemetryCollectorStatus{
+			TLS: &v1alpha1.TLSStatus{Fingerprint: "the-fingerprint"},
+		},
+	}
+
+	// test
+	annotations := Annotations(otelcol)
+
+	// verify
+	assert.Equal(t, "the-fingerprint", annotations[AnnotationTLSFingerprint])
+	assert.Equal(t, getConfigMapSHA(Config(otelcol)), annotations["opentelemetry-operator-config/sha256"])
+	assert.NotEqual(t, getConfigMapSHA(otelcol.Spec.Config), annotations["opentelemetry-operator-config/sha256"])
+}
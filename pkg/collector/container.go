@@ -23,14 +23,21 @@ import (
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
 )
 
+// Image returns the container image for the given collector: the one from its spec or, when not set, the operator's
+// default image for the collector's mode.
+func Image(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) string {
+	if len(otelcol.Spec.Image) > 0 {
+		return otelcol.Spec.Image
+	}
+	return cfg.CollectorImageForMode(otelcol.Spec.Mode)
+}
+
 // Container builds a container for the given collector.
 func Container(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) corev1.Container {
-	image := otelcol.Spec.Image
-	if len(image) == 0 {
-		image = cfg.CollectorImage()
-	}
+	image := cfg.RewriteImage(Image(cfg, otelcol))
 
 	argsMap := otelcol.Spec.Args
 	if argsMap == nil {
@@ -54,6 +61,14 @@ func Container(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelem
 		MountPath: "/conf",
 	}}
 
+	if otelcol.Spec.TLS != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.TLSVolume(),
+			MountPath: TLSMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	if len(otelcol.Spec.VolumeMounts) > 0 {
 		volumeMounts = append(volumeMounts, otelcol.Spec.VolumeMounts...)
 	} else if otelcol.Spec.Mode == "statefulset" {
@@ -68,13 +83,111 @@ func Container(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelem
 		envVars = []corev1.EnvVar{}
 	}
 
+	securityContext := otelcol.Spec.SecurityContext
+	if securityContext == nil && cfg.Platform() == platform.OpenShift {
+		securityContext = restrictedSecurityContext()
+	}
+
+	livenessProbe, readinessProbe, err := Probes(otelcol)
+	if err != nil {
+		logger.V(1).Info("the collector container has no probes", "reason", err.Error())
+	}
+
 	return corev1.Container{
 		Name:            naming.Container(),
 		Image:           image,
+		ImagePullPolicy: otelcol.Spec.ImagePullPolicy,
+		Ports:           ContainerPorts(logger, otelcol),
 		VolumeMounts:    volumeMounts,
 		Args:            args,
 		Env:             envVars,
-		Resources:       otelcol.Spec.Resources,
-		SecurityContext: otelcol.Spec.SecurityContext,
+		Resources:       resources(cfg, otelcol),
+		SecurityContext: securityContext,
+		LivenessProbe:   livenessProbe,
+		ReadinessProbe:  readinessProbe,
+	}
+}
+
+// restrictedSecurityContext returns a security context admitted by OpenShift's restricted security context
+// constraints. The user isn't set, as it's assigned from the range of the namespace.
+func restrictedSecurityContext() *corev1.SecurityContext {
+	allowPrivilegeEscalation := false
+	runAsNonRoot := true
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		RunAsNonRoot:             &runAsNonRoot,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// resources returns the compute resources from the spec of the given collector or, when it has none, the operator's
+// default resources.
+func resources(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) corev1.ResourceRequirements {
+	if len(otelcol.Spec.Resources.Limits) > 0 || len(otelcol.Spec.Resources.Requests) > 0 {
+		return otelcol.Spec.Resources
+	}
+	return cfg.CollectorResources()
+}
+
+// InitContainers returns the instance's init containers, with the operator's image rewrite rules applied.
+func InitContainers(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) []corev1.Container {
+	return withRewrittenImages(cfg, otelcol.Spec.InitContainers)
+}
+
+// AdditionalContainers returns the instance's containers running next to the collector, with the operator's image
+// rewrite rules applied.
+func AdditionalContainers(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) []corev1.Container {
+	return withRewrittenImages(cfg, otelcol.Spec.AdditionalContainers)
+}
+
+// ValidateContainers returns an error when the instance's init and additional containers can't be added to its pods.
+func ValidateContainers(otelcol v1alpha1.OpenTelemetryCollector) error {
+	names := map[string]bool{naming.Container(): true}
+	for _, container := range append(append([]corev1.Container{}, otelcol.Spec.InitContainers...), otelcol.Spec.AdditionalContainers...) {
+		if container.Name == naming.Container() {
+			return fmt.Errorf("the container name %q is reserved for the OpenTelemetry Collector", container.Name)
+		}
+		if names[container.Name] {
+			return fmt.Errorf("the container name %q is used more than once", container.Name)
+		}
+		names[container.Name] = true
+	}
+	return nil
+}
+
+func withRewrittenImages(cfg config.Config, containers []corev1.Container) []corev1.Container {
+	if len(containers) == 0 {
+		return nil
+	}
+	rewritten := make([]corev1.Container, len(containers))
+	for i := range containers {
+		containers[i].DeepCopyInto(&rewritten[i])
+		rewritten[i].Image = cfg.RewriteImage(containers[i].Image)
+	}
+	return rewritten
+}
+
+// ImagePullSecrets returns the image pull secrets of the pods of the given collector: the ones from its spec, followed
+// by the operator's default pull secrets.
+func ImagePullSecrets(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) []corev1.LocalObjectReference {
+	return AppendImagePullSecrets(AppendImagePullSecrets(nil, otelcol.Spec.ImagePullSecrets), cfg.ImagePullSecrets())
+}
+
+// AppendImagePullSecrets appends the given secrets that aren't in the list yet. It returns nil when both are empty.
+func AppendImagePullSecrets(secrets, more []corev1.LocalObjectReference) []corev1.LocalObjectReference {
+	for _, secret := range more {
+		found := false
+		for _, existing := range secrets {
+			if existing.Name == secret.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			secrets = append(secrets, secret)
+		}
 	}
+	return secrets
 }
@@ -19,13 +19,18 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
 )
 
 var logger = logf.Log.WithName("unit-tests")
@@ -58,6 +63,210 @@ func TestContainerWithImageOverridden(t *testing.T) {
 	assert.Equal(t, "overridden-image", c.Image)
 }
 
+func TestContainerDefaultsFromTheConfigurationFile(t *testing.T) {
+	// prepare
+	cfg := config.New(config.WithCollectorImage("default-image"))
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Collector: configv1alpha1.CollectorDefaults{
+			Images: configv1alpha1.CollectorImages{Sidecar: "sidecar-image"},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+	})
+	withResources := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode: v1alpha1.ModeSidecar,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			},
+		},
+	}
+
+	// test
+	deployment := Container(cfg, logger, v1alpha1.OpenTelemetryCollector{Spec: v1alpha1.OpenTelemetryCollectorSpec{Mode: v1alpha1.ModeDeployment}})
+	sidecar := Container(cfg, logger, withResources)
+
+	// verify
+	assert.Equal(t, "default-image", deployment.Image)
+	assert.Equal(t, resource.MustParse("256Mi"), deployment.Resources.Limits[corev1.ResourceMemory])
+	assert.Equal(t, "sidecar-image", sidecar.Image)
+	assert.Equal(t, withResources.Spec.Resources, sidecar.Resources)
+}
+
+func TestContainerImageRewrittenByTheConfigurationFile(t *testing.T) {
+	// prepare
+	cfg := config.New(config.WithCollectorImage("otel/opentelemetry-collector:0.31.0"))
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Images: configv1alpha1.ImageSettings{
+			Rewrites: []configv1alpha1.ImageRewrite{{From: "docker.io/", To: "registry.example.com/"}},
+		},
+	})
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			ImagePullPolicy: corev1.PullIfNotPresent,
+		},
+	}
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	assert.Equal(t, "registry.example.com/otel/opentelemetry-collector:0.31.0", c.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, c.ImagePullPolicy)
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Images: configv1alpha1.ImageSettings{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "shared"}, {Name: "operator"}},
+		},
+	})
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "instance"}, {Name: "shared"}},
+		},
+	}
+
+	// test
+	secrets := ImagePullSecrets(cfg, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "instance"}, {Name: "shared"}, {Name: "operator"}}, secrets)
+	assert.Nil(t, ImagePullSecrets(config.New(), v1alpha1.OpenTelemetryCollector{}))
+}
+
+func TestAdditionalContainersWithRewrittenImages(t *testing.T) {
+	// prepare
+	cfg := config.New()
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Images: configv1alpha1.ImageSettings{
+			Rewrites: []configv1alpha1.ImageRewrite{{From: "docker.io/", To: "registry.example.com/"}},
+		},
+	})
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			InitContainers:       []corev1.Container{{Name: "fetch-config", Image: "busybox:1.33"}},
+			AdditionalContainers: []corev1.Container{{Name: "ship-logs", Image: "ghcr.io/example/shipper:1.0"}},
+		},
+	}
+
+	// test
+	initContainers := InitContainers(cfg, otelcol)
+	additionalContainers := AdditionalContainers(cfg, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.Container{{Name: "fetch-config", Image: "registry.example.com/library/busybox:1.33"}}, initContainers)
+	assert.Equal(t, []corev1.Container{{Name: "ship-logs", Image: "ghcr.io/example/shipper:1.0"}}, additionalContainers)
+	assert.Equal(t, "busybox:1.33", otelcol.Spec.InitContainers[0].Image)
+	assert.Nil(t, InitContainers(cfg, v1alpha1.OpenTelemetryCollector{}))
+}
+
+func TestValidateContainers(t *testing.T) {
+	for _, tt := range []struct {
+		desc           string
+		initContainers []corev1.Container
+		containers     []corev1.Container
+		expected       string
+	}{
+		{"valid", []corev1.Container{{Name: "fetch-config"}}, []corev1.Container{{Name: "ship-logs"}}, ""},
+		{"collector's name", nil, []corev1.Container{{Name: "otc-container"}}, "reserved"},
+		{"collector's name for an init container", []corev1.Container{{Name: "otc-container"}}, nil, "reserved"},
+		{"duplicate name", []corev1.Container{{Name: "helper"}}, []corev1.Container{{Name: "helper"}}, "more than once"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					InitContainers:       tt.initContainers,
+					AdditionalContainers: tt.containers,
+				},
+			}
+
+			// test
+			err := ValidateContainers(otelcol)
+
+			// verify
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestContainerProbes(t *testing.T) {
+	// prepare
+	failureThreshold := int32(5)
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: `extensions:
+  health_check:
+    endpoint: 0.0.0.0:8080
+    path: /health
+service:
+  extensions: [health_check]
+`,
+			ReadinessProbe: &v1alpha1.ProbeSpec{FailureThreshold: &failureThreshold},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	require.NotNil(t, c.LivenessProbe)
+	require.NotNil(t, c.ReadinessProbe)
+	assert.Equal(t, &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080)}, c.LivenessProbe.HTTPGet)
+	assert.Equal(t, c.LivenessProbe.HTTPGet, c.ReadinessProbe.HTTPGet)
+	assert.Equal(t, int32(0), c.LivenessProbe.FailureThreshold)
+	assert.Equal(t, int32(5), c.ReadinessProbe.FailureThreshold)
+}
+
+func TestContainerProbesInSidecarMode(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode: v1alpha1.ModeSidecar,
+			Config: `extensions:
+  health_check:
+service:
+  extensions: [health_check]
+`,
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	assert.Nil(t, c.LivenessProbe)
+	assert.Nil(t, c.ReadinessProbe)
+}
+
+func TestContainerWithoutHealthCheck(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "receivers:\n  otlp:\n",
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	assert.Nil(t, c.LivenessProbe)
+	assert.Nil(t, c.ReadinessProbe)
+}
+
 func TestContainerConfigFlagIsIgnored(t *testing.T) {
 	// prepare
 	otelcol := v1alpha1.OpenTelemetryCollector{
@@ -98,6 +307,25 @@ func TestContainerCustomVolumes(t *testing.T) {
 	assert.Equal(t, "custom-volume-mount", c.VolumeMounts[1].Name)
 }
 
+func TestContainerWithTLS(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			TLS: &v1alpha1.TLSSpec{},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	assert.Len(t, c.VolumeMounts, 2)
+	assert.Equal(t, naming.TLSVolume(), c.VolumeMounts[1].Name)
+	assert.Equal(t, TLSMountPath, c.VolumeMounts[1].MountPath)
+	assert.True(t, c.VolumeMounts[1].ReadOnly)
+}
+
 func TestContainerCustomSecurityContext(t *testing.T) {
 	// default config without security context
 	c1 := Container(config.New(), logger, v1alpha1.OpenTelemetryCollector{Spec: v1alpha1.OpenTelemetryCollectorSpec{}})
@@ -125,6 +353,28 @@ func TestContainerCustomSecurityContext(t *testing.T) {
 	assert.Equal(t, *c2.SecurityContext.RunAsUser, uid)
 }
 
+func TestContainerSecurityContextOnOpenShift(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{}
+	cfg := config.New(config.WithPlatform(platform.OpenShift))
+
+	// test
+	c := Container(cfg, logger, otelcol)
+
+	// verify
+	require.NotNil(t, c.SecurityContext)
+	assert.False(t, *c.SecurityContext.AllowPrivilegeEscalation)
+	assert.True(t, *c.SecurityContext.RunAsNonRoot)
+	assert.Nil(t, c.SecurityContext.RunAsUser)
+	assert.Equal(t, []corev1.Capability{"ALL"}, c.SecurityContext.Capabilities.Drop)
+
+	// the security context from the spec takes precedence
+	privileged := true
+	otelcol.Spec.SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	c = Container(cfg, logger, otelcol)
+	assert.Equal(t, otelcol.Spec.SecurityContext, c.SecurityContext)
+}
+
 func TestContainerEnvVarsOverridden(t *testing.T) {
 	otelcol := v1alpha1.OpenTelemetryCollector{
 		Spec: v1alpha1.OpenTelemetryCollectorSpec{
@@ -27,10 +27,10 @@ import (
 
 // DaemonSet builds the deployment for the given instance.
 func DaemonSet(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) appsv1.DaemonSet {
-	labels := Labels(otelcol)
+	labels := withDefaults(cfg.CollectorLabels(), Labels(otelcol))
 	labels["app.kubernetes.io/name"] = naming.Collector(otelcol)
 
-	annotations := Annotations(otelcol)
+	annotations := withDefaults(cfg.CollectorAnnotations(), Annotations(otelcol))
 
 	return appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -41,20 +41,22 @@ func DaemonSet(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelem
 		},
 		Spec: appsv1.DaemonSetSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
+				MatchLabels: SelectorLabels(otelcol),
 			},
-			Template: corev1.PodTemplateSpec{
+			Template: withPodTemplateOverlay(logger, otelcol, corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: otelcol.Annotations,
+					Annotations: withDefaults(cfg.CollectorAnnotations(), otelcol.Annotations),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccountName(otelcol),
-					Containers:         []corev1.Container{Container(cfg, logger, otelcol)},
+					ImagePullSecrets:   ImagePullSecrets(cfg, otelcol),
+					InitContainers:     InitContainers(cfg, otelcol),
+					Containers:         append([]corev1.Container{Container(cfg, logger, otelcol)}, AdditionalContainers(cfg, otelcol)...),
 					Volumes:            Volumes(cfg, otelcol),
 					Tolerations:        otelcol.Spec.Tolerations,
 				},
-			},
+			}),
 		},
 	}
 }
@@ -53,6 +53,9 @@ func TestDaemonSetNewDefault(t *testing.T) {
 	// none of the default annotations should propagate down to the pod
 	assert.Empty(t, d.Spec.Template.Annotations)
 
-	// the pod selector should match the pod spec's labels
-	assert.Equal(t, d.Spec.Selector.MatchLabels, d.Spec.Template.Labels)
+	// the pod selector should be the stable subset of the pod spec's labels
+	assert.Equal(t, SelectorLabels(otelcol), d.Spec.Selector.MatchLabels)
+	for k, v := range d.Spec.Selector.MatchLabels {
+		assert.Equal(t, v, d.Spec.Template.Labels[k])
+	}
 }
@@ -22,6 +22,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	configv1alpha1 "github.com/open-telemetry/opentelemetry-operator/api/config/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
@@ -63,6 +64,70 @@ func TestDeploymentNewDefault(t *testing.T) {
 	// none of the default annotations should propagate down to the pod
 	assert.Empty(t, d.Spec.Template.Annotations)
 
-	// the pod selector should match the pod spec's labels
-	assert.Equal(t, d.Spec.Template.Labels, d.Spec.Selector.MatchLabels)
+	// the pod selector should be the stable subset of the pod spec's labels
+	assert.Equal(t, SelectorLabels(otelcol), d.Spec.Selector.MatchLabels)
+	for k, v := range d.Spec.Selector.MatchLabels {
+		assert.Equal(t, v, d.Spec.Template.Labels[k])
+	}
+}
+
+func TestDeploymentDefaultLabelsAndAnnotations(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-instance",
+			Labels:      map[string]string{"team": "from-instance"},
+			Annotations: map[string]string{"owner": "from-instance"},
+		},
+	}
+	cfg := config.New()
+	cfg.Apply(&configv1alpha1.OperatorConfiguration{
+		Collector: configv1alpha1.CollectorDefaults{
+			Labels:      map[string]string{"team": "default", "cost-center": "42"},
+			Annotations: map[string]string{"owner": "default", "example.com/scrape": "false"},
+		},
+	})
+
+	// test
+	d := Deployment(cfg, logger, otelcol)
+
+	// verify
+	for _, labels := range []map[string]string{d.Labels, d.Spec.Template.Labels} {
+		assert.Equal(t, "from-instance", labels["team"])
+		assert.Equal(t, "42", labels["cost-center"])
+	}
+	for _, annotations := range []map[string]string{d.Annotations, d.Spec.Template.Annotations} {
+		assert.Equal(t, "from-instance", annotations["owner"])
+		assert.Equal(t, "false", annotations["example.com/scrape"])
+	}
+	assert.NotContains(t, d.Spec.Selector.MatchLabels, "cost-center")
+}
+
+func TestDeploymentWithAdditionalContainers(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			InitContainers: []v1.Container{{Name: "fetch-config", Image: "busybox:1.33"}},
+			AdditionalContainers: []v1.Container{{
+				Name:         "ship-logs",
+				Image:        "busybox:1.33",
+				VolumeMounts: []v1.VolumeMount{{Name: "otc-internal", MountPath: "/conf"}},
+			}},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	d := Deployment(cfg, logger, otelcol)
+
+	// verify
+	spec := d.Spec.Template.Spec
+	assert.Equal(t, otelcol.Spec.InitContainers, spec.InitContainers)
+	assert.Len(t, spec.Containers, 2)
+	assert.Equal(t, "otc-container", spec.Containers[0].Name)
+	assert.Equal(t, otelcol.Spec.AdditionalContainers[0], spec.Containers[1])
+	assert.Equal(t, "otc-internal", spec.Volumes[0].Name)
 }
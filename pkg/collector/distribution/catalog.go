@@ -0,0 +1,327 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package distribution holds the catalog of the OpenTelemetry Collector distributions, along with the components
+// included in each one of them.
+package distribution
+
+import (
+	// embeds the built-in catalog.
+	_ "embed"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
+)
+
+//go:embed catalog.yaml
+var builtin []byte
+
+// Component is a component included in a distribution, optionally only for a range of versions.
+type Component struct {
+	// Name is the type of the component, like otlp.
+	Name string `json:"name"`
+
+	// Since is the first version including the component.
+	Since string `json:"since,omitempty"`
+
+	// Until is the first version not including the component anymore.
+	Until string `json:"until,omitempty"`
+}
+
+// Components lists the components included in a distribution, by kind.
+type Components struct {
+	Receivers  []Component `json:"receivers,omitempty"`
+	Processors []Component `json:"processors,omitempty"`
+	Exporters  []Component `json:"exporters,omitempty"`
+	Extensions []Component `json:"extensions,omitempty"`
+}
+
+// Distribution is an OpenTelemetry Collector distribution.
+type Distribution struct {
+	// Name identifies the distribution in the OpenTelemetryCollector resources.
+	Name string `json:"name"`
+
+	// Image is the repository of the distribution's images, without the tag, like otel/opentelemetry-collector.
+	Image string `json:"image,omitempty"`
+
+	// Extends is the name of another distribution whose components are all included in this distribution as well.
+	Extends string `json:"extends,omitempty"`
+
+	// Components are the components included in the distribution, in addition to the ones it extends.
+	Components Components `json:"components"`
+}
+
+type catalogFile struct {
+	Distributions []Distribution `json:"distributions"`
+}
+
+// Catalog holds the known distributions.
+type Catalog struct {
+	distributions map[string]Distribution
+}
+
+// Load builds a catalog with the built-in distributions, along with the ones from the given files. The distributions
+// from the files replace the known distributions with the same name. Empty file names are ignored.
+func Load(files ...string) (*Catalog, error) {
+	c := &Catalog{distributions: map[string]Distribution{}}
+	if err := c.add(builtin); err != nil {
+		return nil, fmt.Errorf("failed to load the built-in distributions: %w", err)
+	}
+
+	for _, file := range files {
+		if len(file) == 0 {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the distributions from %s: %w", file, err)
+		}
+		if err := c.add(data); err != nil {
+			return nil, fmt.Errorf("failed to load the distributions from %s: %w", file, err)
+		}
+	}
+
+	for _, name := range c.Names() {
+		if _, err := c.resolve(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Catalog) add(data []byte) error {
+	file := catalogFile{}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return err
+	}
+
+	for _, d := range file.Distributions {
+		if err := c.Register(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register adds the distribution to the catalog, replacing the known distribution with the same name.
+func (c *Catalog) Register(d Distribution) error {
+	if len(d.Name) == 0 {
+		return fmt.Errorf("the distributions require a name")
+	}
+
+	for _, components := range d.Components.byKind() {
+		for _, component := range components.list {
+			for _, v := range []string{component.Since, component.Until} {
+				if _, err := parseVersion(v); err != nil {
+					return fmt.Errorf("invalid version for the %s %q of the distribution %q: %w", components.kind, component.Name, d.Name, err)
+				}
+			}
+		}
+	}
+
+	c.distributions[d.Name] = d
+	return nil
+}
+
+// Names returns the names of the known distributions, sorted.
+func (c *Catalog) Names() []string {
+	names := make([]string, 0, len(c.distributions))
+	for name := range c.distributions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the distribution with the given name, including the components from the distributions it extends.
+func (c *Catalog) Get(name string) (Distribution, bool) {
+	d, err := c.resolve(name)
+	if err != nil {
+		return Distribution{}, false
+	}
+	return d, true
+}
+
+// ForImage returns the name of the distribution of the given image, or an empty string when the image's repository
+// isn't the one from a known distribution.
+func (c *Catalog) ForImage(image string) string {
+	repository := Repository(image)
+	if len(repository) == 0 {
+		return ""
+	}
+
+	for _, name := range c.Names() {
+		if d := c.distributions[name]; len(d.Image) > 0 && Repository(d.Image) == repository {
+			return name
+		}
+	}
+	return ""
+}
+
+// Validate returns an error when the configuration of the instance uses components that aren't included in the
+// instance's distribution, at the version from the instance's image. Instances without a distribution aren't validated.
+func (c *Catalog) Validate(otelcol *v1alpha1.OpenTelemetryCollector) error {
+	if len(otelcol.Spec.Distribution) == 0 {
+		return nil
+	}
+
+	d, ok := c.Get(otelcol.Spec.Distribution)
+	if !ok {
+		return fmt.Errorf("unknown OpenTelemetry Collector distribution %q, the known distributions are: %s", otelcol.Spec.Distribution, strings.Join(c.Names(), ", "))
+	}
+
+	config, err := adapters.ConfigFromString(otelcol.Spec.Config)
+	if err != nil {
+		return fmt.Errorf("failed to validate the components of the configuration: %w", err)
+	}
+
+	ver := collector.ImageVersion(otelcol.Spec.Image)
+	missing := d.Missing(config, ver)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	distribution := fmt.Sprintf("the %s distribution", d.Name)
+	if ver != nil {
+		distribution = fmt.Sprintf("%s v%s", distribution, ver)
+	}
+	return fmt.Errorf("the configuration uses components that aren't available in %s of the OpenTelemetry Collector: %s", distribution, strings.Join(missing, ", "))
+}
+
+// Missing returns the components from the given configuration that aren't included in the distribution, at the given
+// version. When the version is nil, the components are considered included regardless of their versions.
+func (d Distribution) Missing(config map[interface{}]interface{}, ver *semver.Version) []string {
+	missing := []string{}
+	for _, components := range d.Components.byKind() {
+		section, ok := config[components.section].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		names := []string{}
+		for key := range section {
+			name, ok := key.(string)
+			if !ok {
+				continue
+			}
+			if typ := strings.SplitN(name, "/", 2)[0]; !components.includes(typ, ver) {
+				names = append(names, name)
+			}
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			missing = append(missing, fmt.Sprintf("%s %q", components.kind, name))
+		}
+	}
+	return missing
+}
+
+// resolve returns the distribution with the given name, merging the components from the distributions it extends.
+func (c *Catalog) resolve(name string) (Distribution, error) {
+	d, ok := c.distributions[name]
+	if !ok {
+		return d, fmt.Errorf("unknown distribution %q", name)
+	}
+
+	resolved := d
+	resolved.Components = Components{}
+	seen := map[string]bool{}
+	for current := d; ; {
+		if seen[current.Name] {
+			return d, fmt.Errorf("the distribution %q extends itself", name)
+		}
+		seen[current.Name] = true
+
+		resolved.Components.Receivers = append(resolved.Components.Receivers, current.Components.Receivers...)
+		resolved.Components.Processors = append(resolved.Components.Processors, current.Components.Processors...)
+		resolved.Components.Exporters = append(resolved.Components.Exporters, current.Components.Exporters...)
+		resolved.Components.Extensions = append(resolved.Components.Extensions, current.Components.Extensions...)
+
+		if len(current.Extends) == 0 {
+			return resolved, nil
+		}
+		next, ok := c.distributions[current.Extends]
+		if !ok {
+			return d, fmt.Errorf("the distribution %q extends the unknown distribution %q", name, current.Extends)
+		}
+		current = next
+	}
+}
+
+// componentList holds the components of one kind, along with the configuration section they are declared in.
+type componentList struct {
+	kind    string
+	section string
+	list    []Component
+}
+
+func (c Components) byKind() []componentList {
+	return []componentList{
+		{"receiver", "receivers", c.Receivers},
+		{"processor", "processors", c.Processors},
+		{"exporter", "exporters", c.Exporters},
+		{"extension", "extensions", c.Extensions},
+	}
+}
+
+func (l componentList) includes(typ string, ver *semver.Version) bool {
+	for _, component := range l.list {
+		if component.Name != typ {
+			continue
+		}
+		if ver == nil {
+			return true
+		}
+
+		// the versions have been validated when the distribution was registered
+		since, _ := parseVersion(component.Since)
+		until, _ := parseVersion(component.Until)
+		if (since == nil || !ver.LessThan(since)) && (until == nil || ver.LessThan(until)) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) (*semver.Version, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	return semver.NewVersion(v)
+}
+
+// Repository returns the repository of the given image, without its tag or digest, and without the default registry.
+func Repository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i >= 0 && i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	for _, registry := range []string{"docker.io/", "index.docker.io/"} {
+		image = strings.TrimPrefix(image, registry)
+	}
+	return image
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distribution
+
+import (
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestBuiltinDistributions(t *testing.T) {
+	// test
+	c, err := Load()
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{"contrib", "core"}, c.Names())
+
+	contrib, ok := c.Get("contrib")
+	require.True(t, ok)
+	assert.Empty(t, contrib.Missing(map[interface{}]interface{}{
+		"receivers": map[interface{}]interface{}{"otlp": nil, "filelog": nil},
+	}, nil), "the contrib distribution should include the components from the core distribution")
+}
+
+func TestForImage(t *testing.T) {
+	// prepare
+	c, err := Load()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		image    string
+		expected string
+	}{
+		{"otel/opentelemetry-collector:0.29.0", "core"},
+		{"docker.io/otel/opentelemetry-collector-contrib:0.29.0", "contrib"},
+		{"otel/opentelemetry-collector-contrib@sha256:1234", "contrib"},
+		{"quay.io/someone/collector:0.29.0", ""},
+		{"", ""},
+	} {
+		t.Run(tt.image, func(t *testing.T) {
+			// test
+			name := c.ForImage(tt.image)
+
+			// verify
+			assert.Equal(t, tt.expected, name)
+		})
+	}
+}
+
+func TestMissingComponents(t *testing.T) {
+	// prepare
+	c, err := Load()
+	require.NoError(t, err)
+	core, ok := c.Get("core")
+	require.True(t, ok)
+
+	config := map[interface{}]interface{}{
+		"receivers": map[interface{}]interface{}{
+			"otlp":      nil,
+			"filelog/2": nil,
+			"filelog/1": nil,
+		},
+		"exporters": map[interface{}]interface{}{
+			"jaeger": nil,
+			"debug":  nil,
+		},
+	}
+
+	for _, tt := range []struct {
+		version  string
+		expected []string
+	}{
+		{"", []string{`receiver "filelog/1"`, `receiver "filelog/2"`}},
+		{"0.29.0", []string{`receiver "filelog/1"`, `receiver "filelog/2"`, `exporter "debug"`}},
+		{"0.86.0", []string{`receiver "filelog/1"`, `receiver "filelog/2"`, `exporter "jaeger"`}},
+	} {
+		t.Run(tt.version, func(t *testing.T) {
+			var ver *semver.Version
+			if len(tt.version) > 0 {
+				ver = semver.MustParse(tt.version)
+			}
+
+			// test
+			missing := core.Missing(config, ver)
+
+			// verify
+			assert.Equal(t, tt.expected, missing)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// prepare
+	c, err := Load()
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		desc         string
+		distribution string
+		image        string
+		config       string
+		expected     string
+	}{
+		{"no distribution", "", "", "receivers:\n  filelog:\n", ""},
+		{"included components", "contrib", "", "receivers:\n  filelog:\nexporters:\n  logging:\n", ""},
+		{"unknown distribution", "unknown", "", "", `unknown OpenTelemetry Collector distribution "unknown", the known distributions are: contrib, core`},
+		{"missing components", "core", "", "receivers:\n  filelog:\n", `the configuration uses components that aren't available in the core distribution of the OpenTelemetry Collector: receiver "filelog"`},
+		{"missing components for the version", "core", "otel/opentelemetry-collector:0.29.0", "exporters:\n  debug:\n", `the configuration uses components that aren't available in the core distribution v0.29.0 of the OpenTelemetry Collector: exporter "debug"`},
+		{"invalid configuration", "core", "", "receivers: [", "failed to validate the components of the configuration"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Config:       tt.config,
+					Distribution: tt.distribution,
+					Image:        tt.image,
+				},
+			}
+
+			// test
+			err := c.Validate(&otelcol)
+
+			// verify
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadCustomDistributions(t *testing.T) {
+	// test
+	c, err := Load("testdata/custom.yaml")
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "contrib", "core"}, c.Names())
+	assert.Equal(t, "acme", c.ForImage("registry.example.com/acme/otelcol:0.31.0"))
+
+	acme, ok := c.Get("acme")
+	require.True(t, ok)
+	config := map[interface{}]interface{}{
+		"receivers": map[interface{}]interface{}{"acme": nil, "filelog": nil, "otlp": nil},
+	}
+	assert.Empty(t, acme.Missing(config, semver.MustParse("0.31.0")))
+	assert.Equal(t, []string{`receiver "acme"`}, acme.Missing(config, semver.MustParse("0.29.0")))
+
+	// the core distribution has been replaced
+	core, ok := c.Get("core")
+	require.True(t, ok)
+	assert.Equal(t, []string{`exporter "logging"`}, core.Missing(map[interface{}]interface{}{
+		"exporters": map[interface{}]interface{}{"otlp": nil, "logging": nil},
+	}, nil))
+}
+
+func TestLoadInvalidDistributions(t *testing.T) {
+	for _, tt := range []struct {
+		file     string
+		expected string
+	}{
+		{"testdata/unknown-extends.yaml", `the distribution "broken" extends the unknown distribution "unknown"`},
+		{"testdata/extends-itself.yaml", `the distribution "itself" extends itself`},
+		{"testdata/invalid-version.yaml", `invalid version for the receiver "otlp" of the distribution "broken"`},
+		{"testdata/non-existing.yaml", "failed to read the distributions from testdata/non-existing.yaml"},
+	} {
+		t.Run(tt.file, func(t *testing.T) {
+			// test
+			_, err := Load(tt.file)
+
+			// verify
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -30,10 +30,37 @@ func Labels(instance v1alpha1.OpenTelemetryCollector) map[string]string {
 		}
 	}
 
-	base["app.kubernetes.io/managed-by"] = "opentelemetry-operator"
-	base["app.kubernetes.io/instance"] = fmt.Sprintf("%s.%s", instance.Namespace, instance.Name)
-	base["app.kubernetes.io/part-of"] = "opentelemetry"
-	base["app.kubernetes.io/component"] = "opentelemetry-collector"
+	for k, v := range SelectorLabels(instance) {
+		base[k] = v
+	}
 
 	return base
 }
+
+// SelectorLabels return the minimal set of labels that identify the pods of a managed OpenTelemetryCollector.
+// Workload selectors are immutable, so this set must not change over time nor depend on the instance's labels.
+func SelectorLabels(instance v1alpha1.OpenTelemetryCollector) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "opentelemetry-operator",
+		"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", instance.Namespace, instance.Name),
+		"app.kubernetes.io/part-of":    "opentelemetry",
+		"app.kubernetes.io/component":  "opentelemetry-collector",
+	}
+}
+
+// withDefaults returns the given values along with the defaults they don't override. The values are returned as
+// they are when there are no defaults.
+func withDefaults(defaults, values map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return values
+	}
+
+	merged := make(map[string]string, len(defaults)+len(values))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged
+}
@@ -56,3 +56,24 @@ func TestLabelsPropagateDown(t *testing.T) {
 	assert.Len(t, labels, 5)
 	assert.Equal(t, "mycomponent", labels["myapp"])
 }
+
+func TestSelectorLabelsIgnoreInstanceLabels(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-instance",
+			Namespace: "my-ns",
+			Labels:    map[string]string{"myapp": "mycomponent"},
+		},
+	}
+
+	// test
+	labels := SelectorLabels(otelcol)
+
+	// verify
+	assert.Len(t, labels, 4)
+	assert.Equal(t, "opentelemetry-operator", labels["app.kubernetes.io/managed-by"])
+	assert.Equal(t, "my-ns.my-instance", labels["app.kubernetes.io/instance"])
+	assert.Equal(t, "opentelemetry", labels["app.kubernetes.io/part-of"])
+	assert.Equal(t, "opentelemetry-collector", labels["app.kubernetes.io/component"])
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+)
+
+// ApplyPodTemplateOverlay applies the instance's pod template overlay to the given pod template. It returns an error
+// when the overlay isn't a valid strategic merge patch for the template, or when it breaks the operator-managed
+// container or its configuration volume.
+func ApplyPodTemplateOverlay(otelcol v1alpha1.OpenTelemetryCollector, template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	if otelcol.Spec.PodTemplateOverlay == nil || len(otelcol.Spec.PodTemplateOverlay.Raw) == 0 {
+		return template, nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return template, fmt.Errorf("failed to serialize the pod template: %w", err)
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(original, otelcol.Spec.PodTemplateOverlay.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return template, fmt.Errorf("failed to apply the pod template overlay: %w", err)
+	}
+
+	patched := corev1.PodTemplateSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patched); err != nil {
+		return template, fmt.Errorf("the pod template overlay is invalid: %w", err)
+	}
+
+	if err := checkManagedFields(otelcol, template, patched); err != nil {
+		return template, err
+	}
+
+	return patched, nil
+}
+
+// ValidatePodTemplateOverlay dry-runs the instance's pod template overlay against the pod template the operator builds
+// for it, or against the injected sidecar in the sidecar mode.
+func ValidatePodTemplateOverlay(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) error {
+	if otelcol.Spec.PodTemplateOverlay == nil {
+		return nil
+	}
+
+	// the template is built without the overlay, which is applied afterwards
+	withoutOverlay := *otelcol.DeepCopy()
+	withoutOverlay.Spec.PodTemplateOverlay = nil
+
+	var template corev1.PodTemplateSpec
+	switch otelcol.Spec.Mode {
+	case v1alpha1.ModeDaemonSet:
+		template = DaemonSet(cfg, logger, withoutOverlay).Spec.Template
+	case v1alpha1.ModeStatefulSet:
+		template = StatefulSet(cfg, logger, withoutOverlay).Spec.Template
+	case v1alpha1.ModeSidecar:
+		_, _, err := ApplySidecarOverlay(otelcol, Container(cfg, logger, withoutOverlay), Volumes(cfg, withoutOverlay))
+		return err
+	default:
+		template = Deployment(cfg, logger, withoutOverlay).Spec.Template
+	}
+
+	_, err := ApplyPodTemplateOverlay(otelcol, template)
+	return err
+}
+
+// ApplySidecarOverlay applies the instance's pod template overlay to the given sidecar container and its volumes. The
+// pod the sidecar is injected into belongs to the application, so the overlay can only change the sidecar container
+// and add volumes: it returns an error when it sets the metadata, other pod fields or other containers.
+func ApplySidecarOverlay(otelcol v1alpha1.OpenTelemetryCollector, container corev1.Container, volumes []corev1.Volume) (corev1.Container, []corev1.Volume, error) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{container},
+			Volumes:    volumes,
+		},
+	}
+
+	patched, err := ApplyPodTemplateOverlay(otelcol, template)
+	if err != nil {
+		return container, volumes, err
+	}
+
+	if !apiequality.Semantic.DeepEqual(patched.ObjectMeta, template.ObjectMeta) {
+		return container, volumes, errors.New("the pod template overlay of a sidecar can't change the metadata of the pod it's injected into")
+	}
+	if len(patched.Spec.Containers) != 1 {
+		return container, volumes, fmt.Errorf("the pod template overlay of a sidecar can only change the container %q", naming.Container())
+	}
+	podFields := patched.Spec
+	podFields.Containers, podFields.Volumes = nil, nil
+	if !apiequality.Semantic.DeepEqual(podFields, corev1.PodSpec{}) {
+		return container, volumes, errors.New("the pod template overlay of a sidecar can't change the fields of the pod it's injected into, only its container and volumes")
+	}
+
+	return patched.Spec.Containers[0], patched.Spec.Volumes, nil
+}
+
+// withPodTemplateOverlay applies the instance's pod template overlay to the given template. The template is used as it
+// is when the overlay can't be applied, which the webhook prevents unless it's disabled.
+func withPodTemplateOverlay(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector, template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	patched, err := ApplyPodTemplateOverlay(otelcol, template)
+	if err != nil {
+		logger.Error(err, "ignoring the pod template overlay", "namespace", otelcol.Namespace, "name", otelcol.Name)
+		return template
+	}
+	return patched
+}
+
+// checkManagedFields returns an error when the patched template lost the labels matched by the workload's selector,
+// the operator-managed container, or the configuration volume it's using.
+func checkManagedFields(otelcol v1alpha1.OpenTelemetryCollector, original, patched corev1.PodTemplateSpec) error {
+	for key, value := range SelectorLabels(otelcol) {
+		if original.Labels[key] == value && patched.Labels[key] != value {
+			return fmt.Errorf("the pod template overlay changes the label %q, used by the operator to select the pods", key)
+		}
+	}
+
+	name := naming.Container()
+	container := findContainer(original.Spec.Containers, name)
+	if container == nil {
+		return nil
+	}
+
+	patchedContainer := findContainer(patched.Spec.Containers, name)
+	if patchedContainer == nil {
+		return fmt.Errorf("the pod template overlay removes the operator-managed container %q", name)
+	}
+	if patchedContainer.Image != container.Image {
+		return fmt.Errorf("the pod template overlay changes the image of the operator-managed container %q, use the attribute 'image' instead", name)
+	}
+	if !apiequality.Semantic.DeepEqual(patchedContainer.Args, container.Args) {
+		return fmt.Errorf("the pod template overlay changes the arguments of the operator-managed container %q, use the attribute 'args' instead", name)
+	}
+
+	volumeName := naming.ConfigMapVolume()
+	for _, mount := range container.VolumeMounts {
+		if mount.Name != volumeName {
+			continue
+		}
+		found := false
+		for _, patchedMount := range patchedContainer.VolumeMounts {
+			if patchedMount.Name == volumeName && patchedMount.MountPath == mount.MountPath {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("the pod template overlay changes the mount of the configuration volume %q in the operator-managed container %q", volumeName, name)
+		}
+	}
+
+	for _, volume := range original.Spec.Volumes {
+		if volume.Name != volumeName {
+			continue
+		}
+		patchedVolume := findVolume(patched.Spec.Volumes, volumeName)
+		if patchedVolume == nil {
+			return fmt.Errorf("the pod template overlay removes the configuration volume %q", volumeName)
+		}
+		if !apiequality.Semantic.DeepEqual(patchedVolume.VolumeSource, volume.VolumeSource) {
+			return fmt.Errorf("the pod template overlay changes the source of the configuration volume %q", volumeName)
+		}
+	}
+
+	return nil
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+func findVolume(volumes []corev1.Volume, name string) *corev1.Volume {
+	for i := range volumes {
+		if volumes[i].Name == name {
+			return &volumes[i]
+		}
+	}
+	return nil
+}
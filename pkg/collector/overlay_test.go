@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestDeploymentWithPodTemplateOverlay(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			PodTemplateOverlay: &runtime.RawExtension{Raw: []byte(`{
+				"metadata": {"labels": {"team": "observability"}},
+				"spec": {
+					"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["backend.example.com"]}],
+					"securityContext": {"sysctls": [{"name": "net.core.somaxconn", "value": "1024"}]},
+					"containers": [{"name": "otc-container", "env": [{"name": "GOGC", "value": "80"}]}]
+				}
+			}`)},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	d := Deployment(cfg, logger, otelcol)
+
+	// verify
+	template := d.Spec.Template
+	assert.Equal(t, "observability", template.Labels["team"])
+	assert.Equal(t, "my-instance-collector", template.Labels["app.kubernetes.io/name"])
+	assert.Equal(t, []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"backend.example.com"}}}, template.Spec.HostAliases)
+	require.NotNil(t, template.Spec.SecurityContext)
+	assert.Equal(t, []corev1.Sysctl{{Name: "net.core.somaxconn", Value: "1024"}}, template.Spec.SecurityContext.Sysctls)
+
+	require.Len(t, template.Spec.Containers, 1)
+	container := template.Spec.Containers[0]
+	assert.Equal(t, "otc-container", container.Name)
+	assert.NotEmpty(t, container.Image)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "GOGC", Value: "80"})
+	assert.Len(t, template.Spec.Volumes, 1)
+}
+
+func TestInvalidPodTemplateOverlayIsIgnored(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-instance",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode:               v1alpha1.ModeDaemonSet,
+			PodTemplateOverlay: &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": [{"name": "otc-container", "$patch": "delete"}]}}`)},
+		},
+	}
+	cfg := config.New()
+
+	// test
+	d := DaemonSet(cfg, logger, otelcol)
+
+	// verify
+	require.Len(t, d.Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, "otc-container", d.Spec.Template.Spec.Containers[0].Name)
+}
+
+func TestValidatePodTemplateOverlay(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		mode     v1alpha1.Mode
+		overlay  string
+		expected string
+	}{
+		{"valid", v1alpha1.ModeDeployment, `{"spec": {"priorityClassName": "high"}}`, ""},
+		{"valid in the sidecar mode", v1alpha1.ModeSidecar, `{"spec": {"containers": [{"name": "otc-container", "env": [{"name": "GOGC", "value": "80"}]}]}}`, ""},
+		{"not an object", v1alpha1.ModeDeployment, `[]`, "failed to apply the pod template overlay"},
+		{"unknown field", v1alpha1.ModeDeployment, `{"spec": {"hostAlias": []}}`, "the pod template overlay is invalid"},
+		{"container removed", v1alpha1.ModeStatefulSet, `{"spec": {"containers": [{"name": "otc-container", "$patch": "delete"}]}}`, "removes the operator-managed container"},
+		{"pod field in the sidecar mode", v1alpha1.ModeSidecar, `{"spec": {"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["backend"]}]}}`, "can't change the fields of the pod"},
+		{"other container in the sidecar mode", v1alpha1.ModeSidecar, `{"spec": {"containers": [{"name": "my-app", "image": "other"}]}}`, "can only change the container"},
+		{"metadata in the sidecar mode", v1alpha1.ModeSidecar, `{"metadata": {"annotations": {"my-annotation": "my-value"}}}`, "can't change the metadata"},
+		{"containers replaced", v1alpha1.ModeSidecar, `{"spec": {"containers": [{"name": "other", "image": "other"}], "$patch": "replace"}}`, "removes the operator-managed container"},
+		{"image changed", v1alpha1.ModeDeployment, `{"spec": {"containers": [{"name": "otc-container", "image": "other"}]}}`, "use the attribute 'image' instead"},
+		{"args changed", v1alpha1.ModeDeployment, `{"spec": {"containers": [{"name": "otc-container", "args": ["--help"]}]}}`, "use the attribute 'args' instead"},
+		{"volume mount removed", v1alpha1.ModeDeployment, `{"spec": {"containers": [{"name": "otc-container", "volumeMounts": [{"mountPath": "/conf", "$patch": "delete"}]}]}}`, "mount of the configuration volume"},
+		{"volume removed", v1alpha1.ModeDaemonSet, `{"spec": {"volumes": [{"name": "otc-internal", "$patch": "delete"}]}}`, "removes the configuration volume"},
+		{"volume source changed", v1alpha1.ModeDeployment, `{"spec": {"volumes": [{"name": "otc-internal", "configMap": {"name": "other"}}]}}`, "changes the source of the configuration volume"},
+		{"selector label changed", v1alpha1.ModeDeployment, `{"metadata": {"labels": {"app.kubernetes.io/instance": "other"}}}`, "used by the operator to select the pods"},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-instance",
+					Namespace: "observability",
+				},
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Mode:               tt.mode,
+					PodTemplateOverlay: &runtime.RawExtension{Raw: []byte(tt.overlay)},
+				},
+			}
+
+			// test
+			err := ValidatePodTemplateOverlay(config.New(), logger, otelcol)
+
+			// verify
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -21,9 +21,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/internal/version"
 )
 
 var (
@@ -56,10 +59,31 @@ func BuilderFor(name string) Builder {
 	return builder
 }
 
-// For returns a new parser for the given receiver name + config.
+// For returns a new parser for the given receiver name + config, using the default ports of the OpenTelemetry
+// Collector version deployed by default.
 func For(logger logr.Logger, name string, config map[interface{}]interface{}) ReceiverParser {
+	return ForVersion(logger, name, nil, config)
+}
+
+// ForVersion returns a new parser for the given receiver name + config, using the default ports of the given
+// OpenTelemetry Collector version. When the version is nil, the version deployed by default is used.
+func ForVersion(logger logr.Logger, name string, ver *semver.Version, config map[interface{}]interface{}) ReceiverParser {
 	builder := BuilderFor(name)
-	return builder(logger, name, config)
+	parser := builder(logger, name, config)
+
+	if versioned, ok := parser.(versionedParser); ok {
+		if ver == nil {
+			ver, _ = semver.NewVersion(version.OpenTelemetryCollector())
+		}
+		versioned.setVersion(ver)
+	}
+
+	return parser
+}
+
+// versionedParser is implemented by the parsers whose default ports depend on the OpenTelemetry Collector version.
+type versionedParser interface {
+	setVersion(*semver.Version)
 }
 
 // Register adds a new parser builder to the list of known builders.
@@ -17,6 +17,7 @@ package parser
 import (
 	"fmt"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -27,15 +28,21 @@ var _ ReceiverParser = &OTLPReceiverParser{}
 const (
 	parserNameOTLP = "__otlp"
 
-	defaultOTLPGRPCPort int32 = 4317
-	defaultOTLPHTTPPort int32 = 55681
+	defaultOTLPGRPCPort       int32 = 4317
+	defaultOTLPHTTPPort       int32 = 4318
+	defaultLegacyOTLPHTTPPort int32 = 55681
 )
 
+// otlpHTTPPortChange is the first OpenTelemetry Collector version using defaultOTLPHTTPPort for OTLP over HTTP,
+// instead of defaultLegacyOTLPHTTPPort.
+var otlpHTTPPortChange = semver.MustParse("0.31.0")
+
 // OTLPReceiverParser parses the configuration for OTLP receivers.
 type OTLPReceiverParser struct {
-	logger logr.Logger
-	name   string
-	config map[interface{}]interface{}
+	logger  logr.Logger
+	name    string
+	config  map[interface{}]interface{}
+	version *semver.Version
 }
 
 // NewOTLPReceiverParser builds a new parser for OTLP receivers.
@@ -54,10 +61,19 @@ func NewOTLPReceiverParser(logger logr.Logger, name string, config map[interface
 	}
 }
 
+func (o *OTLPReceiverParser) setVersion(version *semver.Version) {
+	o.version = version
+}
+
 // Ports returns all the service ports for all protocols in this parser.
 func (o *OTLPReceiverParser) Ports() ([]corev1.ServicePort, error) {
 	ports := []corev1.ServicePort{}
 
+	httpPort := defaultLegacyOTLPHTTPPort
+	if o.version != nil && !o.version.LessThan(otlpHTTPPortChange) {
+		httpPort = defaultOTLPHTTPPort
+	}
+
 	for _, protocol := range []struct {
 		name         string
 		defaultPorts []corev1.ServicePort
@@ -75,9 +91,9 @@ func (o *OTLPReceiverParser) Ports() ([]corev1.ServicePort, error) {
 		{
 			name: "http",
 			defaultPorts: []corev1.ServicePort{{
-				Name:       portName(fmt.Sprintf("%s-http", o.name), defaultOTLPHTTPPort),
-				Port:       defaultOTLPHTTPPort,
-				TargetPort: intstr.FromInt(int(defaultOTLPHTTPPort)),
+				Name:       portName(fmt.Sprintf("%s-http", o.name), httpPort),
+				Port:       httpPort,
+				TargetPort: intstr.FromInt(int(httpPort)),
 			}},
 		},
 	} {
@@ -17,6 +17,7 @@ package parser
 import (
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,3 +85,31 @@ func TestOTLPExposeDefaultPorts(t *testing.T) {
 		assert.True(t, v.seen, "the port %s wasn't included in the service ports", k)
 	}
 }
+
+func TestOTLPHTTPDefaultPortForVersion(t *testing.T) {
+	for _, tt := range []struct {
+		version  string
+		expected int32
+	}{
+		{"0.29.0", 55681},
+		{"0.31.0", 4318},
+		{"0.86.0", 4318},
+	} {
+		t.Run(tt.version, func(t *testing.T) {
+			// prepare
+			builder := ForVersion(logger, "otlp", semver.MustParse(tt.version), map[interface{}]interface{}{
+				"protocols": map[interface{}]interface{}{
+					"http": map[interface{}]interface{}{},
+				},
+			})
+
+			// test
+			ports, err := builder.Ports()
+
+			// verify
+			assert.NoError(t, err)
+			assert.Len(t, ports, 1)
+			assert.EqualValues(t, tt.expected, ports[0].Port)
+		})
+	}
+}
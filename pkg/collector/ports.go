@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
+)
+
+// ContainerPorts returns the ports of the collector container: the ones from the instance's spec, followed by the
+// ones of the receivers from its configuration and by the port of the collector's own metrics, see MetricsPort. The ports already
+// declared with the same number and protocol are skipped. In the daemonset mode, the ports listed in the spec's host
+// ports are also exposed on the nodes.
+func ContainerPorts(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) []corev1.ContainerPort {
+	hostPorts := map[string]int32{}
+	if otelcol.Spec.Mode == v1alpha1.ModeDaemonSet {
+		for _, hostPort := range otelcol.Spec.HostPorts {
+			hostPorts[hostPort.Name] = hostPort.HostPort
+		}
+	}
+
+	servicePorts := append([]corev1.ServicePort{}, otelcol.Spec.Ports...)
+	servicePorts = append(servicePorts, receiverPorts(logger, otelcol)...)
+	servicePorts = append(servicePorts, corev1.ServicePort{Name: "metrics", Port: MetricsPort(otelcol)})
+
+	ports := []corev1.ContainerPort{}
+	declared := map[string]int{}
+	names := map[string]bool{}
+	for _, servicePort := range servicePorts {
+		port := corev1.ContainerPort{
+			ContainerPort: servicePort.Port,
+			Protocol:      servicePort.Protocol,
+		}
+		if servicePort.TargetPort.Type == intstr.Int && servicePort.TargetPort.IntVal > 0 {
+			port.ContainerPort = servicePort.TargetPort.IntVal
+		}
+		if len(port.Protocol) == 0 {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if hostPort, ok := hostPorts[servicePort.Name]; ok {
+			port.HostPort = hostPort
+			if port.HostPort == 0 {
+				port.HostPort = port.ContainerPort
+			}
+		}
+
+		key := fmt.Sprintf("%d/%s", port.ContainerPort, port.Protocol)
+		if i, ok := declared[key]; ok {
+			// the host port might be requested for the name of the port that was skipped
+			if ports[i].HostPort == 0 {
+				ports[i].HostPort = port.HostPort
+			}
+			continue
+		}
+		declared[key] = len(ports)
+
+		port.Name = containerPortName(servicePort.Name, port.ContainerPort, names)
+		if len(port.Name) > 0 {
+			names[port.Name] = true
+		}
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// MetricsPort returns the port of the collector's own metrics, exposed by the monitoring service: the one from the
+// address in the telemetry settings of the instance's configuration, or the collector's default, 8888, when the
+// address isn't set or can't be parsed.
+func MetricsPort(otelcol v1alpha1.OpenTelemetryCollector) int32 {
+	config, err := adapters.ConfigFromString(otelcol.Spec.Config)
+	if err != nil {
+		return adapters.DefaultMetricsPort
+	}
+
+	port, err := adapters.ConfigToMetricsPort(config)
+	if err != nil {
+		return adapters.DefaultMetricsPort
+	}
+	return port
+}
+
+// ValidateHostPorts returns an error when the instance's host ports don't refer to the ports of its receivers, or to
+// the ports from its spec.
+func ValidateHostPorts(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) error {
+	if len(otelcol.Spec.HostPorts) == 0 {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, port := range append(append([]corev1.ServicePort{}, otelcol.Spec.Ports...), receiverPorts(logger, otelcol)...) {
+		names[port.Name] = true
+	}
+
+	for _, hostPort := range otelcol.Spec.HostPorts {
+		if !names[hostPort.Name] {
+			return fmt.Errorf("the host port %q doesn't match the name of a receiver port, nor of a port from the attribute 'ports'", hostPort.Name)
+		}
+	}
+	return nil
+}
+
+// receiverPorts returns the ports of the receivers from the instance's configuration, sorted so that the container
+// doesn't change from one reconciliation to the next.
+func receiverPorts(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) []corev1.ServicePort {
+	config, err := adapters.ConfigFromString(otelcol.Spec.Config)
+	if err != nil {
+		logger.V(1).Info("couldn't infer the ports of the receivers", "reason", err.Error())
+		return nil
+	}
+
+	ports, err := adapters.ConfigToReceiverPortsForVersion(logger, config, TargetVersion(otelcol))
+	if err != nil {
+		logger.V(1).Info("couldn't infer the ports of the receivers", "reason", err.Error())
+		return nil
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+	return ports
+}
+
+// containerPortName returns the given name when it's a valid container port name that isn't used yet, like
+// otlp-grpc. Longer names, which are fine for the services, fall back to port-<number>. An empty name is returned
+// when the fallback is used already.
+func containerPortName(name string, port int32, used map[string]bool) string {
+	if len(validation.IsValidPortName(name)) == 0 && !used[name] {
+		return name
+	}
+
+	fallback := fmt.Sprintf("port-%d", port)
+	if used[fallback] {
+		return ""
+	}
+	return fallback
+}
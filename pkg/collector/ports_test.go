@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestContainerPorts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+  jaeger:
+    protocols:
+      thrift_compact:
+  zipkin:
+`,
+			Ports: []corev1.ServicePort{
+				{Name: "custom", Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Name: "zipkin-overridden", Port: 9411},
+			},
+		},
+	}
+
+	// test
+	ports := ContainerPorts(logger, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: "custom", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+		{Name: "port-9411", ContainerPort: 9411, Protocol: corev1.ProtocolTCP},
+		{Name: "otlp-grpc", ContainerPort: 4317, Protocol: corev1.ProtocolTCP},
+		{Name: "port-6831", ContainerPort: 6831, Protocol: corev1.ProtocolUDP},
+		{Name: "metrics", ContainerPort: 8888, Protocol: corev1.ProtocolTCP},
+	}, ports)
+}
+
+func TestContainerPortsWithoutReceivers(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{}
+
+	// test
+	ports := ContainerPorts(logger, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.ContainerPort{{Name: "metrics", ContainerPort: 8888, Protocol: corev1.ProtocolTCP}}, ports)
+}
+
+func TestContainerPortsWithMetricsAddress(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "service:\n  telemetry:\n    metrics:\n      address: 0.0.0.0:8889\n",
+		},
+	}
+
+	// test
+	ports := ContainerPorts(logger, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.ContainerPort{{Name: "metrics", ContainerPort: 8889, Protocol: corev1.ProtocolTCP}}, ports)
+}
+
+func TestMetricsPort(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   string
+		expected int32
+	}{
+		{"default", "receivers:\n  otlp:\n", 8888},
+		{"address", "service:\n  telemetry:\n    metrics:\n      address: :8889\n", 8889},
+		{"invalid address", "service:\n  telemetry:\n    metrics:\n      address: localhost\n", 8888},
+		{"invalid configuration", "[", 8888},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{Config: tt.config},
+			}
+
+			// test
+			port := MetricsPort(otelcol)
+
+			// verify
+			assert.Equal(t, tt.expected, port)
+		})
+	}
+}
+
+func TestContainerHostPorts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode:   v1alpha1.ModeDaemonSet,
+			Config: "receivers:\n  otlp:\n    protocols:\n      grpc:\n  zipkin:\n",
+			Ports:  []corev1.ServicePort{{Name: "custom", Port: 9411}},
+			HostPorts: []v1alpha1.HostPortSpec{
+				{Name: "otlp-grpc"},
+				{Name: "zipkin", HostPort: 19411},
+			},
+		},
+	}
+
+	// test
+	ports := ContainerPorts(logger, otelcol)
+
+	// verify
+	assert.Equal(t, []corev1.ContainerPort{
+		{Name: "custom", ContainerPort: 9411, HostPort: 19411, Protocol: corev1.ProtocolTCP},
+		{Name: "otlp-grpc", ContainerPort: 4317, HostPort: 4317, Protocol: corev1.ProtocolTCP},
+		{Name: "metrics", ContainerPort: 8888, Protocol: corev1.ProtocolTCP},
+	}, ports)
+}
+
+func TestContainerHostPortsIgnoredOutsideOfDaemonSets(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode:      v1alpha1.ModeDeployment,
+			Config:    "receivers:\n  otlp:\n    protocols:\n      grpc:\n",
+			HostPorts: []v1alpha1.HostPortSpec{{Name: "otlp-grpc"}},
+		},
+	}
+
+	// test
+	ports := ContainerPorts(logger, otelcol)
+
+	// verify
+	for _, port := range ports {
+		assert.Zero(t, port.HostPort)
+	}
+}
+
+func TestValidateHostPorts(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Mode:      v1alpha1.ModeDaemonSet,
+			Config:    "receivers:\n  otlp:\n    protocols:\n      grpc:\n",
+			Ports:     []corev1.ServicePort{{Name: "custom", Port: 9411}},
+			HostPorts: []v1alpha1.HostPortSpec{{Name: "otlp-grpc"}, {Name: "custom"}},
+		},
+	}
+
+	// test
+	valid := ValidateHostPorts(logger, otelcol)
+	otelcol.Spec.HostPorts = append(otelcol.Spec.HostPorts, v1alpha1.HostPortSpec{Name: "otlp-http"})
+	invalid := ValidateHostPorts(logger, otelcol)
+
+	// verify
+	assert.NoError(t, valid)
+	assert.Error(t, invalid)
+	assert.Contains(t, invalid.Error(), `"otlp-http"`)
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
+)
+
+// ErrSidecarProbes is returned by Probes for the instances in the sidecar mode: the probes of the injected container
+// would hold back the readiness of the application's pods, or restart them, when the collector isn't healthy.
+var ErrSidecarProbes = errors.New("the sidecars don't have probes, as they would affect the application's pods")
+
+// Probes returns the liveness and readiness probes of the collector container, checking the health_check extension
+// of the instance's configuration, with the overrides from the instance's spec. The returned error tells why there
+// are no probes, like when the extension isn't enabled or for the sidecars.
+func Probes(otelcol v1alpha1.OpenTelemetryCollector) (*corev1.Probe, *corev1.Probe, error) {
+	if otelcol.Spec.Mode == v1alpha1.ModeSidecar {
+		return nil, nil, ErrSidecarProbes
+	}
+
+	config, err := adapters.ConfigFromString(otelcol.Spec.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	probe, err := adapters.ConfigToContainerProbe(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return withProbeSpec(probe.DeepCopy(), otelcol.Spec.LivenessProbe), withProbeSpec(probe.DeepCopy(), otelcol.Spec.ReadinessProbe), nil
+}
+
+// withProbeSpec applies the settings from the given spec to the probe.
+func withProbeSpec(probe *corev1.Probe, spec *v1alpha1.ProbeSpec) *corev1.Probe {
+	if spec == nil {
+		return probe
+	}
+	if spec.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *spec.InitialDelaySeconds
+	}
+	if spec.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *spec.TimeoutSeconds
+	}
+	if spec.PeriodSeconds != nil {
+		probe.PeriodSeconds = *spec.PeriodSeconds
+	}
+	if spec.SuccessThreshold != nil {
+		probe.SuccessThreshold = *spec.SuccessThreshold
+	}
+	if spec.FailureThreshold != nil {
+		probe.FailureThreshold = *spec.FailureThreshold
+	}
+	return probe
+}
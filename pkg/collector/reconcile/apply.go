@@ -0,0 +1,336 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// FieldManager is the name of the field manager used by the operator when applying the owned objects.
+const FieldManager = "opentelemetry-operator"
+
+// ownedKind holds the kind-specific parts of the reconciliation of the objects owned by an instance.
+type ownedKind struct {
+	// name is the plural name of the kind, used in logs and error messages
+	name string
+
+	// list returns an empty list for this kind, used to find the objects to prune
+	list func() client.ObjectList
+
+	// desired returns the objects of this kind that should exist for the instance
+	desired func(ctx context.Context, params Params) []client.Object
+
+	// immutableFieldChanged returns the path of an immutable field with a different value in the desired object, if any
+	immutableFieldChanged func(desired, existing client.Object) (string, bool)
+
+	// onChange is called after changes to an existing object have been applied
+	onChange func(params Params, desired, existing client.Object)
+
+	// available returns whether the kind exists in the cluster, when it's not always the case
+	available func(params Params) bool
+}
+
+// isAvailable returns whether the objects of this kind can be reconciled in the cluster.
+func (k ownedKind) isAvailable(params Params) bool {
+	return k.available == nil || k.available(params)
+}
+
+// reconcileOwned applies the desired objects and prunes the objects of the same kind that aren't desired anymore.
+func reconcileOwned(ctx context.Context, params Params, kind ownedKind) error {
+	if !kind.isAvailable(params) {
+		return nil
+	}
+
+	desired := kind.desired(ctx, params)
+
+	// first, handle the create/update parts
+	if err := applyOwned(ctx, params, kind, desired); err != nil {
+		return fmt.Errorf("failed to reconcile the expected %s: %w", kind.name, err)
+	}
+
+	// then, delete the extra objects
+	if err := pruneOwned(ctx, params, kind, desired); err != nil {
+		return fmt.Errorf("failed to reconcile the %s to be deleted: %w", kind.name, err)
+	}
+
+	return nil
+}
+
+// applyOwned uses server-side apply to bring the desired objects to the cluster. Only the fields set by the builders
+// are owned by the operator, so that fields set by other actors (like the replicas set by an HPA) are preserved.
+// Objects that already contain the desired state, with the operator owning exactly the desired fields, are left
+// untouched: as the manager's client reads from the informer cache, no request is sent to the API server for them.
+// When a field isn't desired anymore, the object is applied so that the API server removes it.
+func applyOwned(ctx context.Context, params Params, kind ownedKind, expected []client.Object) error {
+	for _, obj := range expected {
+		desired, gvk, err := prepare(params, obj)
+		if err != nil {
+			return err
+		}
+
+		log := params.Log.WithValues("kind", gvk.Kind, "name", desired.GetName(), "namespace", desired.GetNamespace())
+
+		existing, err := getExisting(ctx, params, gvk, desired)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			if kind.immutableFieldChanged != nil {
+				// immutable fields can't be patched: the object is deleted now and created again in the next reconciliation
+				if field, changed := kind.immutableFieldChanged(desired, existing); changed {
+					if err := recreate(ctx, params, gvk.Kind, existing, field); err != nil {
+						return err
+					}
+					objectWrites.WithLabelValues(gvk.Kind, resultRecreated).Inc()
+					continue
+				}
+			}
+
+			same, err := isUpToDate(desired, existing)
+			if err != nil {
+				return err
+			}
+			if same {
+				log.V(2).Info("unchanged")
+				objectWrites.WithLabelValues(gvk.Kind, resultSkipped).Inc()
+				continue
+			}
+		}
+
+		if err := apply(ctx, params, desired); err != nil {
+			return err
+		}
+
+		if existing == nil {
+			log.V(2).Info("created")
+			objectWrites.WithLabelValues(gvk.Kind, resultCreated).Inc()
+			continue
+		}
+
+		if kind.onChange != nil {
+			kind.onChange(params, desired, existing)
+		}
+		log.V(2).Info("applied")
+		objectWrites.WithLabelValues(gvk.Kind, resultApplied).Inc()
+	}
+
+	return nil
+}
+
+// prepare returns a copy of the desired object, with the controller reference and the type information set.
+func prepare(params Params, obj client.Object) (client.Object, schema.GroupVersionKind, error) {
+	desired := obj.DeepCopyObject().(client.Object)
+
+	if err := controllerutil.SetControllerReference(&params.Instance, desired, params.Scheme); err != nil {
+		return nil, schema.GroupVersionKind{}, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	// server-side apply requires the type information to be part of the request
+	gvk, err := apiutil.GVKForObject(desired, params.Scheme)
+	if err != nil {
+		return nil, schema.GroupVersionKind{}, fmt.Errorf("failed to determine the kind: %w", err)
+	}
+	desired.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return desired, gvk, nil
+}
+
+// getExisting returns the current state of the desired object, or nil when it doesn't exist yet.
+func getExisting(ctx context.Context, params Params, gvk schema.GroupVersionKind, desired client.Object) (client.Object, error) {
+	var existing client.Object
+	if _, ok := desired.(*unstructured.Unstructured); ok {
+		// kinds which aren't part of the scheme, like the ones that only exist on some platforms
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		existing = u
+	} else {
+		empty, err := params.Scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build an object of kind %s: %w", gvk.Kind, err)
+		}
+		existing = empty.(client.Object)
+	}
+
+	nns := types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}
+	err := params.Client.Get(ctx, nns, existing)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get: %w", err)
+	}
+
+	return existing, nil
+}
+
+// apply sends the desired object to the API server. Conflicts with other field managers are reported, and the
+// ownership of the conflicting fields is then taken over, as the operator is the authority over the objects it owns.
+func apply(ctx context.Context, params Params, desired client.Object) error {
+	err := params.Client.Patch(ctx, desired, client.Apply, client.FieldOwner(FieldManager))
+	if k8serrors.IsConflict(err) {
+		params.Recorder.Event(&params.Instance, "Warning", "FieldConflict", fmt.Sprintf("Taking over fields managed by other actors for %s/%s: %s", desired.GetNamespace(), desired.GetName(), err))
+		err = params.Client.Patch(ctx, desired, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to apply changes: %w", err)
+	}
+
+	return nil
+}
+
+// pruneOwned deletes the objects that belong to the instance based on their labels but aren't desired anymore.
+func pruneOwned(ctx context.Context, params Params, kind ownedKind, expected []client.Object) error {
+	extra, err := extraOwned(ctx, params, kind, expected)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range extra {
+		gvk, err := apiutil.GVKForObject(existing, params.Scheme)
+		if err != nil {
+			return fmt.Errorf("failed to determine the kind: %w", err)
+		}
+		if err := params.Client.Delete(ctx, existing); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete: %w", err)
+		}
+		params.Log.V(2).Info("deleted", "name", existing.GetName(), "namespace", existing.GetNamespace())
+		objectWrites.WithLabelValues(gvk.Kind, resultDeleted).Inc()
+	}
+
+	return nil
+}
+
+// extraOwned returns the objects that belong to the instance based on their labels but aren't desired anymore.
+// Objects controlled by another owner are never part of the result.
+func extraOwned(ctx context.Context, params Params, kind ownedKind, expected []client.Object) ([]client.Object, error) {
+	opts := []client.ListOption{
+		client.InNamespace(params.Instance.Namespace),
+		client.MatchingLabels(map[string]string{
+			"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.Instance.Namespace, params.Instance.Name),
+			"app.kubernetes.io/managed-by": "opentelemetry-operator",
+		}),
+	}
+	list := kind.list()
+	if err := params.Client.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list: %w", err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the items from the list: %w", err)
+	}
+
+	extra := []client.Object{}
+	for _, item := range items {
+		existing, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+
+		del := true
+		for _, keep := range expected {
+			if keep.GetName() == existing.GetName() && keep.GetNamespace() == existing.GetNamespace() {
+				del = false
+			}
+		}
+
+		if owner := metav1.GetControllerOf(existing); del && owner != nil && owner.UID != params.Instance.UID {
+			params.Log.V(2).Info("skipping the deletion of an object controlled by another owner", "name", existing.GetName(), "namespace", existing.GetNamespace(), "owner", owner.Name)
+			del = false
+		}
+
+		if del {
+			extra = append(extra, existing)
+		}
+	}
+
+	return extra, nil
+}
+
+// containsDesiredState checks whether all the fields set in the desired object have the same value in the existing one.
+func containsDesiredState(desired, existing client.Object) (bool, error) {
+	d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert the desired object: %w", err)
+	}
+
+	e, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert the existing object: %w", err)
+	}
+
+	// the type information isn't always populated for objects coming from the client. The content of unstructured
+	// objects isn't copied by the conversion, so, the desired object itself must not be changed.
+	withoutType := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		if k != "apiVersion" && k != "kind" {
+			withoutType[k] = v
+		}
+	}
+
+	return isSubset(withoutType, e), nil
+}
+
+// isSubset checks whether the desired value is contained in the existing value. Maps might have extra entries in the
+// existing value, like labels added by other actors or fields with default values, while lists have to have the same
+// number of elements, each being a subset of their counterparts.
+func isSubset(desired, existing interface{}) bool {
+	switch d := desired.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		e, ok := existing.(map[string]interface{})
+		if !ok {
+			return len(d) == 0 && existing == nil
+		}
+		for k, v := range d {
+			if !isSubset(v, e[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		e, ok := existing.([]interface{})
+		if !ok {
+			return len(d) == 0 && existing == nil
+		}
+		if len(d) != len(e) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, existing)
+	}
+}
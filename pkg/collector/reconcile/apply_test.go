@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestApplySkipsUnchangedObjects(t *testing.T) {
+	// prepare
+	cl := &patchCountingClient{Client: k8sClient}
+	param := params()
+	param.Client = cl
+	param.Instance.Name = "test-unchanged"
+	desired := collector.Deployment(param.Config, logger, param.Instance)
+	skipped := testutil.ToFloat64(objectWrites.WithLabelValues("Deployment", resultSkipped))
+
+	// test
+	err := applyOwned(context.Background(), param, deployments, []client.Object{&desired})
+	require.NoError(t, err)
+	err = applyOwned(context.Background(), param, deployments, []client.Object{&desired})
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, 1, cl.patches)
+	assert.Equal(t, skipped+1, testutil.ToFloat64(objectWrites.WithLabelValues("Deployment", resultSkipped)))
+}
+
+func TestApplyPreservesFieldsFromOtherManagers(t *testing.T) {
+	// prepare
+	param := params()
+	param.Instance.Name = "test-hpa"
+	param.Instance.Spec.Replicas = nil
+	desired := collector.Deployment(param.Config, logger, param.Instance)
+	require.NoError(t, applyOwned(context.Background(), param, deployments, []client.Object{&desired}))
+
+	// an autoscaler changes the number of replicas
+	nns := types.NamespacedName{Namespace: "default", Name: "test-hpa-collector"}
+	existing := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(context.Background(), nns, existing))
+	scaled := existing.DeepCopy()
+	replicas := int32(5)
+	scaled.Spec.Replicas = &replicas
+	require.NoError(t, k8sClient.Patch(context.Background(), scaled, client.MergeFrom(existing), client.FieldOwner("autoscaler")))
+
+	// test
+	param.Instance.Annotations = map[string]string{"new-annotation": "true"}
+	desired = collector.Deployment(param.Config, logger, param.Instance)
+	err := applyOwned(context.Background(), param, deployments, []client.Object{&desired})
+
+	// verify
+	require.NoError(t, err)
+	actual := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(context.Background(), nns, actual))
+	assert.Equal(t, int32(5), *actual.Spec.Replicas)
+	assert.Equal(t, "true", actual.Annotations["new-annotation"])
+}
+
+func TestApplyRemovesFieldsNotDesiredAnymore(t *testing.T) {
+	// prepare
+	param := params()
+	param.Instance.Name = "test-removed-field"
+	param.Instance.Annotations = map[string]string{"removed-annotation": "true"}
+	desired := collector.Deployment(param.Config, logger, param.Instance)
+	require.NoError(t, applyOwned(context.Background(), param, deployments, []client.Object{&desired}))
+
+	nns := types.NamespacedName{Namespace: "default", Name: "test-removed-field-collector"}
+	existing := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(context.Background(), nns, existing))
+	require.Equal(t, "true", existing.Annotations["removed-annotation"])
+
+	// test
+	param.Instance.Annotations = nil
+	desired = collector.Deployment(param.Config, logger, param.Instance)
+	err := applyOwned(context.Background(), param, deployments, []client.Object{&desired})
+
+	// verify
+	require.NoError(t, err)
+	actual := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(context.Background(), nns, actual))
+	assert.NotContains(t, actual.Annotations, "removed-annotation")
+	assert.NotContains(t, actual.Spec.Template.Annotations, "removed-annotation")
+}
+
+func TestIsSubset(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		desired  interface{}
+		existing interface{}
+		expected bool
+	}{
+		{
+			"extra entries in the existing map",
+			map[string]interface{}{"a": "b"},
+			map[string]interface{}{"a": "b", "c": "d"},
+			true,
+		},
+		{
+			"different values",
+			map[string]interface{}{"a": "b"},
+			map[string]interface{}{"a": "c"},
+			false,
+		},
+		{
+			"missing entry in the existing map",
+			map[string]interface{}{"a": "b"},
+			map[string]interface{}{},
+			false,
+		},
+		{
+			"unset desired value",
+			map[string]interface{}{"a": nil},
+			map[string]interface{}{"a": "b"},
+			true,
+		},
+		{
+			"empty desired map",
+			map[string]interface{}{"a": map[string]interface{}{}},
+			map[string]interface{}{},
+			true,
+		},
+		{
+			"lists with different sizes",
+			[]interface{}{"a"},
+			[]interface{}{"a", "b"},
+			false,
+		},
+		{
+			"lists with defaulted fields in the existing elements",
+			[]interface{}{map[string]interface{}{"name": "a"}},
+			[]interface{}{map[string]interface{}{"name": "a", "protocol": "TCP"}},
+			true,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isSubset(tt.desired, tt.existing))
+		})
+	}
+}
+
+func TestContainsDesiredStateKeepsUnstructuredObjects(t *testing.T) {
+	// prepare
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"a": "b"}}}
+	desired.SetGroupVersionKind(routeGVK)
+	existing := desired.DeepCopy()
+
+	// test
+	same, err := containsDesiredState(desired, existing)
+
+	// verify
+	require.NoError(t, err)
+	assert.True(t, same)
+	assert.Equal(t, routeGVK, desired.GroupVersionKind())
+}
+
+type patchCountingClient struct {
+	client.Client
+	patches int
+}
+
+func (c *patchCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patches++
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
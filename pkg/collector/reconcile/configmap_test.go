@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
@@ -71,7 +72,8 @@ func TestDesiredConfigMap(t *testing.T) {
 
 func TestExpectedConfigMap(t *testing.T) {
 	t.Run("should create config map", func(t *testing.T) {
-		err := expectedConfigMaps(context.Background(), params(), []v1.ConfigMap{desiredConfigMap(context.Background(), params())}, true)
+		desired := desiredConfigMap(context.Background(), params())
+		err := applyOwned(context.Background(), params(), configMaps, []client.Object{&desired})
 		assert.NoError(t, err)
 
 		exists, err := populateObjectIfExists(t, &v1.ConfigMap{}, types.NamespacedName{Namespace: "default", Name: "test-collector"})
@@ -103,7 +105,8 @@ func TestExpectedConfigMap(t *testing.T) {
 		cm := desiredConfigMap(context.Background(), param)
 		createObjectIfNotExists(t, "test-collector", &cm)
 
-		err := expectedConfigMaps(context.Background(), params(), []v1.ConfigMap{desiredConfigMap(context.Background(), params())}, true)
+		desired := desiredConfigMap(context.Background(), params())
+		err := applyOwned(context.Background(), params(), configMaps, []client.Object{&desired})
 		assert.NoError(t, err)
 
 		actual := v1.ConfigMap{}
@@ -132,7 +135,8 @@ func TestExpectedConfigMap(t *testing.T) {
 		exists, _ := populateObjectIfExists(t, &v1.ConfigMap{}, types.NamespacedName{Namespace: "default", Name: "test-delete-collector"})
 		assert.True(t, exists)
 
-		err := deleteConfigMaps(context.Background(), params(), []v1.ConfigMap{desiredConfigMap(context.Background(), params())})
+		desired := desiredConfigMap(context.Background(), params())
+		err := pruneOwned(context.Background(), params(), configMaps, []client.Object{&desired})
 		assert.NoError(t, err)
 
 		exists, _ = populateObjectIfExists(t, &v1.ConfigMap{}, types.NamespacedName{Namespace: "default", Name: "test-delete-collector"})
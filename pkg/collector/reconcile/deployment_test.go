@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
 )
@@ -32,7 +33,7 @@ func TestExpectedDeployments(t *testing.T) {
 	expectedDeploy := collector.Deployment(param.Config, logger, param.Instance)
 
 	t.Run("should create deployment", func(t *testing.T) {
-		err := expectedDeployments(context.Background(), param, []v1.Deployment{expectedDeploy})
+		err := applyOwned(context.Background(), param, deployments, []client.Object{&expectedDeploy})
 		assert.NoError(t, err)
 
 		exists, err := populateObjectIfExists(t, &v1.Deployment{}, types.NamespacedName{Namespace: "default", Name: "test-collector"})
@@ -43,7 +44,7 @@ func TestExpectedDeployments(t *testing.T) {
 	})
 	t.Run("should update deployment", func(t *testing.T) {
 		createObjectIfNotExists(t, "test-collector", &expectedDeploy)
-		err := expectedDeployments(context.Background(), param, []v1.Deployment{expectedDeploy})
+		err := applyOwned(context.Background(), param, deployments, []client.Object{&expectedDeploy})
 		assert.NoError(t, err)
 
 		actual := v1.Deployment{}
@@ -82,7 +83,7 @@ func TestExpectedDeployments(t *testing.T) {
 		}
 		createObjectIfNotExists(t, "dummy", &deploy)
 
-		err := deleteDeployments(context.Background(), param, []v1.Deployment{expectedDeploy})
+		err := pruneOwned(context.Background(), param, deployments, []client.Object{&expectedDeploy})
 		assert.NoError(t, err)
 
 		actual := v1.Deployment{}
@@ -118,7 +119,7 @@ func TestExpectedDeployments(t *testing.T) {
 		}
 		createObjectIfNotExists(t, "dummy", &deploy)
 
-		err := deleteDeployments(context.Background(), param, []v1.Deployment{expectedDeploy})
+		err := pruneOwned(context.Background(), param, deployments, []client.Object{&expectedDeploy})
 		assert.NoError(t, err)
 
 		actual := v1.Deployment{}
@@ -127,4 +128,27 @@ func TestExpectedDeployments(t *testing.T) {
 		assert.True(t, exists)
 
 	})
+
+	t.Run("should recreate deployment when the selector changes", func(t *testing.T) {
+		changed := params()
+		changed.Instance.Name = "test-selector"
+
+		// the previous versions of the operator used all the labels as the selector
+		existing := collector.Deployment(changed.Config, logger, changed.Instance)
+		existing.Spec.Selector.MatchLabels = existing.Spec.Template.Labels
+		createObjectIfNotExists(t, "test-selector-collector", &existing)
+
+		desired := collector.Deployment(changed.Config, logger, changed.Instance)
+		err := applyOwned(context.Background(), changed, deployments, []client.Object{&desired})
+		assert.NoError(t, err)
+
+		actual := v1.Deployment{}
+		exists, err := populateObjectIfExists(t, &actual, types.NamespacedName{Namespace: "default", Name: "test-selector-collector"})
+		assert.NoError(t, err)
+
+		// the dependents are orphaned, so the object might still be around until the garbage collector is done with it
+		if exists {
+			assert.NotNil(t, actual.DeletionTimestamp)
+		}
+	})
 }
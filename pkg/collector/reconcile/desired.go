@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// ownedKinds are the kinds of the objects owned by an instance.
+var ownedKinds = []ownedKind{configMaps, serviceAccounts, services, routes, deployments, daemonSets, statefulSets}
+
+// Desired returns all the objects that the reconciliation tasks would create for the instance, with their type
+// information set. Only the configuration, the instance, the logger and the scheme from the params are used, so that
+// this can be called without a connection to a cluster. The controller reference isn't set, as it depends on the
+// UID of the instance in the cluster.
+func Desired(ctx context.Context, params Params) ([]client.Object, error) {
+	objects := []client.Object{}
+	for _, kind := range ownedKinds {
+		if !kind.isAvailable(params) {
+			continue
+		}
+		for _, obj := range kind.desired(ctx, params) {
+			desired := obj.DeepCopyObject().(client.Object)
+
+			gvk, err := apiutil.GVKForObject(desired, params.Scheme)
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine the kind of the %s: %w", kind.name, err)
+			}
+			desired.GetObjectKind().SetGroupVersionKind(gvk)
+
+			objects = append(objects, desired)
+		}
+	}
+
+	return objects, nil
+}
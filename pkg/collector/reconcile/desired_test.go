@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
+)
+
+func TestDesiredWithoutClient(t *testing.T) {
+	// prepare
+	param := params()
+	param.Client = nil
+	param.Instance.Spec.Mode = v1alpha1.ModeStatefulSet
+
+	// test
+	objects, err := Desired(context.Background(), param)
+
+	// verify
+	require.NoError(t, err)
+	kinds := map[string]int{}
+	for _, obj := range objects {
+		kinds[obj.GetObjectKind().GroupVersionKind().Kind]++
+		assert.Empty(t, obj.GetOwnerReferences())
+	}
+	assert.Equal(t, map[string]int{"ConfigMap": 1, "ServiceAccount": 1, "Service": 3, "StatefulSet": 1}, kinds)
+}
+
+func TestDesiredRoutesOnOpenShift(t *testing.T) {
+	// prepare
+	param := params()
+	param.Client = nil
+	param.Config = config.New(config.WithPlatform(platform.OpenShift))
+	param.Instance.Spec.Route = &v1alpha1.RouteSpec{}
+
+	// test
+	objects, err := Desired(context.Background(), param)
+
+	// verify
+	require.NoError(t, err)
+	kinds := map[string]int{}
+	for _, obj := range objects {
+		kinds[obj.GetObjectKind().GroupVersionKind().Kind]++
+	}
+	assert.Equal(t, 1, kinds["Route"])
+}
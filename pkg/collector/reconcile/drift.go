@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Drift compares the objects desired for the instance with the ones in the cluster, without changing them. Each entry
+// of the result describes an object that would be changed by the reconciliation, in a human readable form.
+func Drift(ctx context.Context, params Params) ([]string, error) {
+	drift := []string{}
+	for _, kind := range ownedKinds {
+		if !kind.isAvailable(params) {
+			continue
+		}
+		expected := kind.desired(ctx, params)
+		for _, obj := range expected {
+			desired, gvk, err := prepare(params, obj)
+			if err != nil {
+				return nil, err
+			}
+
+			existing, err := getExisting(ctx, params, gvk, desired)
+			if err != nil {
+				return nil, err
+			}
+
+			id := fmt.Sprintf("%s %s/%s", gvk.Kind, desired.GetNamespace(), desired.GetName())
+			if existing == nil {
+				drift = append(drift, fmt.Sprintf("%s: missing, would be created", id))
+				continue
+			}
+
+			if kind.immutableFieldChanged != nil {
+				if field, changed := kind.immutableFieldChanged(desired, existing); changed {
+					drift = append(drift, fmt.Sprintf("%s: the immutable field %q differs, would be recreated", id, field))
+					continue
+				}
+			}
+
+			same, err := isUpToDate(desired, existing)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				drift = append(drift, fmt.Sprintf("%s: differs from the desired state, would be updated", id))
+			}
+		}
+
+		extra, err := extraOwned(ctx, params, kind, expected)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the %s to be deleted: %w", kind.name, err)
+		}
+		for _, existing := range extra {
+			gvk, err := apiutil.GVKForObject(existing, params.Scheme)
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine the kind: %w", err)
+			}
+			drift = append(drift, fmt.Sprintf("%s %s/%s: not desired anymore, would be deleted", gvk.Kind, existing.GetNamespace(), existing.GetName()))
+		}
+	}
+
+	return drift, nil
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestDrift(t *testing.T) {
+	// prepare
+	param := params()
+	param.Instance.Name = "test-drift"
+	param.Instance.Spec.Mode = v1alpha1.ModeDeployment
+	missing := "Deployment default/test-drift-collector: missing, would be created"
+
+	// test
+	drift, err := Drift(context.Background(), param)
+
+	// verify
+	require.NoError(t, err)
+	assert.Contains(t, drift, missing)
+
+	// test
+	require.NoError(t, Deployments(context.Background(), param))
+	drift, err = Drift(context.Background(), param)
+
+	// verify
+	require.NoError(t, err)
+	assert.NotContains(t, drift, missing)
+	for _, d := range drift {
+		assert.NotContains(t, d, "Deployment default/test-drift-collector")
+	}
+
+	// test
+	param.Instance.Spec.Mode = v1alpha1.ModeDaemonSet
+	drift, err = Drift(context.Background(), param)
+
+	// verify
+	require.NoError(t, err)
+	assert.Contains(t, drift, "Deployment default/test-drift-collector: not desired anymore, would be deleted")
+	assert.Contains(t, drift, "DaemonSet default/test-drift-collector: missing, would be created")
+}
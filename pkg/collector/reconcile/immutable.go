@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recreate deletes the given object so that it can be created with the desired state during the next reconciliation.
+// This is required when an immutable field has changed, as the object can't be patched in that case. The dependents
+// are orphaned, so that the existing pods and their persistent volume claims are kept and adopted by the new object.
+func recreate(ctx context.Context, params Params, kind string, existing client.Object, field string) error {
+	if err := params.Client.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s with a change in the immutable field %q: %w", kind, field, err)
+	}
+
+	params.Recorder.Event(&params.Instance, "Normal", "Recreating", fmt.Sprintf("The immutable field %q of the %s %s/%s has changed, recreating it", field, kind, existing.GetNamespace(), existing.GetName()))
+	params.Log.V(2).Info("deleted for recreation", "kind", kind, "name", existing.GetName(), "namespace", existing.GetNamespace(), "field", field)
+	return nil
+}
+
+// selectorChanged checks whether the desired selector is different from the existing one.
+func selectorChanged(desired, existing *metav1.LabelSelector) bool {
+	return !apiequality.Semantic.DeepEqual(desired, existing)
+}
+
+// volumeClaimTemplatesChanged compares only the properties that are set by us, as the API server
+// sets default values for properties like the volume mode and the status.
+func volumeClaimTemplatesChanged(desired, existing []corev1.PersistentVolumeClaim) bool {
+	if len(desired) != len(existing) {
+		return true
+	}
+
+	for i := range desired {
+		d, e := desired[i], existing[i]
+		if d.Name != e.Name {
+			return true
+		}
+
+		if !apiequality.Semantic.DeepEqual(d.Spec.AccessModes, e.Spec.AccessModes) ||
+			!apiequality.Semantic.DeepEqual(d.Spec.Resources, e.Spec.Resources) ||
+			!apiequality.Semantic.DeepEqual(d.Spec.Selector, e.Spec.Selector) ||
+			!apiequality.Semantic.DeepEqual(d.Spec.DataSource, e.Spec.DataSource) {
+			return true
+		}
+
+		if d.Spec.StorageClassName != nil && !apiequality.Semantic.DeepEqual(d.Spec.StorageClassName, e.Spec.StorageClassName) {
+			return true
+		}
+
+		if d.Spec.VolumeMode != nil && !apiequality.Semantic.DeepEqual(d.Spec.VolumeMode, e.Spec.VolumeMode) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestStatefulSetImmutableFieldChanged(t *testing.T) {
+	param := params()
+	param.Instance.Spec.Mode = "statefulset"
+	desired := collector.StatefulSet(param.Config, logger, param.Instance)
+
+	t.Run("should ignore server-side defaults", func(t *testing.T) {
+		existing := desired.DeepCopy()
+		filesystem := corev1.PersistentVolumeFilesystem
+		existing.Spec.VolumeClaimTemplates[0].Spec.VolumeMode = &filesystem
+		existing.Spec.VolumeClaimTemplates[0].Status.Phase = corev1.ClaimPending
+
+		_, changed := statefulSetImmutableFieldChanged(&desired, existing)
+		assert.False(t, changed)
+	})
+
+	t.Run("should detect a changed service name", func(t *testing.T) {
+		existing := desired.DeepCopy()
+		existing.Spec.ServiceName = "another-service"
+
+		field, changed := statefulSetImmutableFieldChanged(&desired, existing)
+		assert.True(t, changed)
+		assert.Equal(t, "spec.serviceName", field)
+	})
+
+	t.Run("should detect a changed volume claim template", func(t *testing.T) {
+		existing := desired.DeepCopy()
+		existing.Spec.VolumeClaimTemplates[0].Name = "another-volume"
+
+		field, changed := statefulSetImmutableFieldChanged(&desired, existing)
+		assert.True(t, changed)
+		assert.Equal(t, "spec.volumeClaimTemplates", field)
+	})
+
+	t.Run("should detect a changed selector", func(t *testing.T) {
+		existing := desired.DeepCopy()
+		existing.Spec.Selector.MatchLabels["app.kubernetes.io/name"] = "test-collector"
+
+		field, changed := statefulSetImmutableFieldChanged(&desired, existing)
+		assert.True(t, changed)
+		assert.Equal(t, "spec.selector", field)
+	})
+}
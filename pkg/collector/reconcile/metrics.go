@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The possible results of the reconciliation of an owned object.
+const (
+	resultCreated   = "created"
+	resultApplied   = "applied"
+	resultSkipped   = "skipped"
+	resultDeleted   = "deleted"
+	resultRecreated = "recreated"
+)
+
+// objectWrites counts the reconciled owned objects, by kind and result. Objects that already have the desired state
+// are counted as "skipped", as no request is sent to the API server for them.
+var objectWrites = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "opentelemetry_operator_owned_object_writes_total",
+		Help: "Number of owned objects reconciled by the operator, by kind and result",
+	},
+	[]string{"kind", "result"},
+)
+
+func init() {
+	// the metrics are exposed by the manager's metrics endpoint
+	metrics.Registry.MustRegister(objectWrites)
+}
@@ -19,24 +19,40 @@ import (
 	"context"
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/version"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
 )
 
 // Self updates this instance's self data. This should be the last item in the reconciliation, as it causes changes
 // making params.Instance obsolete. Default values should be set in the Defaulter webhook, this should only be used
 // for the Status, which can't be set by the defaulter.
 func Self(ctx context.Context, params Params) error {
-	if params.Instance.Status.Version != "" {
-		// a version is already set, let the upgrade mechanism take care of it!
-		return nil
-	}
-
 	changed := params.Instance
-	changed.Status.Version = version.OpenTelemetryCollector()
+	if changed.Status.Version == "" {
+		// this is a new instance: the pods aren't running yet, so, the version comes from the image they'll run
+		changed.Status.Version = version.OpenTelemetryCollector()
+		if imageV := collector.ImageVersion(collector.Image(params.Config, params.Instance)); imageV != nil {
+			changed.Status.Version = imageV.String()
+		}
+	} else {
+		running, err := runningVersion(ctx, params)
+		if err != nil {
+			return err
+		}
+		if running == "" || running == changed.Status.Version {
+			return nil
+		}
+		changed.Status.Version = running
+	}
 
-	// this is only a change for new instances: existing instances are reconciled when the operator is first started
 	statusPatch := client.MergeFrom(&params.Instance)
 	if err := params.Client.Status().Patch(ctx, &changed, statusPatch); err != nil {
 		return fmt.Errorf("failed to apply status changes to the OpenTelemetry CR: %w", err)
@@ -44,3 +60,80 @@ func Self(ctx context.Context, params Params) error {
 
 	return nil
 }
+
+// runningVersion returns the version of the image run by the instance's workload, once it has been rolled out to all
+// the pods. It returns an empty string while the workload is being rolled out, or when the image has no version in its
+// tag. The rollout state comes from the status of the workload, which is cached by the manager as an owned object, so
+// that the pods don't have to be listed.
+func runningVersion(ctx context.Context, params Params) (string, error) {
+	if params.Instance.Spec.Mode == v1alpha1.ModeSidecar {
+		// the sidecars belong to the workloads they're injected into, which only get the new image when restarted
+		return "", nil
+	}
+
+	// the pods run the image with the operator's rewrite rules applied
+	image := params.Config.RewriteImage(collector.Image(params.Config, params.Instance))
+	imageV := collector.ImageVersion(image)
+	if imageV == nil {
+		return "", nil
+	}
+
+	var workload client.Object
+	switch params.Instance.Spec.Mode {
+	case v1alpha1.ModeDaemonSet:
+		workload = &appsv1.DaemonSet{}
+	case v1alpha1.ModeStatefulSet:
+		workload = &appsv1.StatefulSet{}
+	default:
+		workload = &appsv1.Deployment{}
+	}
+
+	nns := types.NamespacedName{Namespace: params.Instance.Namespace, Name: naming.Collector(params.Instance)}
+	if err := params.Client.Get(ctx, nns, workload); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get the workload of the OpenTelemetry Collector: %w", err)
+	}
+
+	template, rolledOut := rolloutState(workload)
+	if !rolledOut {
+		return "", nil
+	}
+
+	for _, container := range template.Spec.Containers {
+		if container.Name == naming.Container() && container.Image == image {
+			return imageV.String(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// rolloutState returns the pod template of the workload, and whether all its pods run that template and are available.
+func rolloutState(workload client.Object) (corev1.PodTemplateSpec, bool) {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		st := w.Status
+		return w.Spec.Template, st.ObservedGeneration >= w.Generation && replicas > 0 &&
+			st.Replicas == replicas && st.UpdatedReplicas == replicas && st.AvailableReplicas == replicas
+	case *appsv1.StatefulSet:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		st := w.Status
+		return w.Spec.Template, st.ObservedGeneration >= w.Generation && replicas > 0 &&
+			st.UpdateRevision == st.CurrentRevision && st.UpdatedReplicas == replicas && st.ReadyReplicas == replicas
+	case *appsv1.DaemonSet:
+		st := w.Status
+		return w.Spec.Template, st.ObservedGeneration >= w.Generation && st.DesiredNumberScheduled > 0 &&
+			st.UpdatedNumberScheduled == st.DesiredNumberScheduled && st.NumberAvailable == st.DesiredNumberScheduled
+	}
+
+	return corev1.PodTemplateSpec{}, false
+}
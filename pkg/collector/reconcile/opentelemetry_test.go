@@ -19,9 +19,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
 )
 
 func TestSelf(t *testing.T) {
@@ -39,4 +44,104 @@ func TestSelf(t *testing.T) {
 		assert.Equal(t, actual.Status.Version, "0.0.0")
 
 	})
+
+	t.Run("should report the version of the image once rolled out", func(t *testing.T) {
+		// prepare
+		p := params()
+		p.Instance.Spec.Image = "otel/opentelemetry-collector:0.29.0"
+		createObjectIfNotExists(t, "test", &p.Instance)
+		p.Instance.Spec.Image = "otel/opentelemetry-collector:0.29.0"
+		p.Instance.Status.Version = "0.0.0"
+
+		deployment := collector.Deployment(p.Config, logger, p.Instance)
+		require.NoError(t, k8sClient.Create(context.Background(), &deployment))
+		defer func() {
+			assert.NoError(t, k8sClient.Delete(context.Background(), &deployment))
+		}()
+
+		// the version isn't reported while the deployment is being rolled out
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3}
+		require.NoError(t, k8sClient.Status().Update(context.Background(), &deployment))
+		running, err := runningVersion(context.Background(), p)
+		require.NoError(t, err)
+		require.Empty(t, running)
+
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+		require.NoError(t, k8sClient.Status().Update(context.Background(), &deployment))
+
+		// test
+		err = Self(context.Background(), p)
+
+		// verify
+		assert.NoError(t, err)
+		actual := v1alpha1.OpenTelemetryCollector{}
+		_, err = populateObjectIfExists(t, &actual, types.NamespacedName{Namespace: "default", Name: "test"})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.29.0", actual.Status.Version)
+	})
+}
+
+func TestRolloutState(t *testing.T) {
+	replicas := int32(2)
+	for _, tt := range []struct {
+		desc     string
+		workload client.Object
+		expected bool
+	}{
+		{
+			"deployment with old pods",
+			&appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+				Status: appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			false,
+		},
+		{
+			"deployment not observed yet",
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			false,
+		},
+		{
+			"statefulset being updated",
+			&appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 2, CurrentRevision: "a", UpdateRevision: "b"},
+			},
+			false,
+		},
+		{
+			"statefulset rolled out",
+			&appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 2, CurrentRevision: "b", UpdateRevision: "b"},
+			},
+			true,
+		},
+		{
+			"daemonset with unavailable pods",
+			&appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+			},
+			false,
+		},
+		{
+			"daemonset rolled out",
+			&appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+			},
+			true,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			_, rolledOut := rolloutState(tt.workload)
+
+			// verify
+			assert.Equal(t, tt.expected, rolledOut)
+		})
+	}
 }
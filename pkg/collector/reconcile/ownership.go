@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"bytes"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// isUpToDate checks whether applying the desired object would leave the existing one unchanged: all the desired
+// values have to be there already, and the fields owned by the operator have to be exactly the ones set in the
+// desired object, as the fields the operator doesn't set anymore are removed by the API server when applying.
+func isUpToDate(desired, existing client.Object) (bool, error) {
+	owned, err := ownsDesiredFields(desired, existing)
+	if err != nil || !owned {
+		return false, err
+	}
+
+	return containsDesiredState(desired, existing)
+}
+
+// ownsDesiredFields compares the fields set in the desired object with the ones recorded for the operator's field
+// manager in the managed fields of the existing object. Objects without such a record are never considered owned.
+func ownsDesiredFields(desired, existing client.Object) (bool, error) {
+	owned, found, err := ownedFields(existing)
+	if err != nil || !found {
+		return false, err
+	}
+
+	d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert the desired object: %w", err)
+	}
+
+	return matchesFields(owned.RecursiveDifference(untrackedFields), withoutUntrackedFields(d)), nil
+}
+
+// ownedFields returns the set of fields applied by the operator to the given object.
+func ownedFields(obj client.Object) (*fieldpath.Set, bool, error) {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager != FieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, false, fmt.Errorf("failed to read the fields managed by %s: %w", FieldManager, err)
+		}
+		return set, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// untrackedFields are the fields ignored when comparing the owned fields: the type information and the identity of the
+// object aren't part of the managed fields, and the status isn't set by the operator, while being recorded or not
+// depending on the version of the API server.
+var untrackedFields = fieldpath.NewSet(
+	fieldpath.MakePathOrDie("apiVersion"),
+	fieldpath.MakePathOrDie("kind"),
+	fieldpath.MakePathOrDie("metadata", "name"),
+	fieldpath.MakePathOrDie("metadata", "namespace"),
+	fieldpath.MakePathOrDie("status"),
+)
+
+// withoutUntrackedFields returns a copy of the desired object without the untracked fields. The content of unstructured
+// objects isn't copied by the conversion, so, the desired object itself must not be changed.
+func withoutUntrackedFields(obj map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		switch k {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			if metadata, ok := v.(map[string]interface{}); ok {
+				m := make(map[string]interface{}, len(metadata))
+				for mk, mv := range metadata {
+					if mk != "name" && mk != "namespace" {
+						m[mk] = mv
+					}
+				}
+				v = m
+			}
+		}
+		res[k] = v
+	}
+	return res
+}
+
+// matchesFields checks whether the owned fields of a map are exactly the non-empty fields of the desired map.
+func matchesFields(owned *fieldpath.Set, desired map[string]interface{}) bool {
+	for k, v := range desired {
+		name := k
+		pe := fieldpath.PathElement{FieldName: &name}
+		child, hasChild := owned.Children.Get(pe)
+
+		if isEmptyValue(v) {
+			// null and empty values aren't recorded consistently across versions, but nothing can be owned below them
+			if hasChild && !child.Empty() {
+				return false
+			}
+			continue
+		}
+
+		switch d := v.(type) {
+		case map[string]interface{}:
+			if !hasChild {
+				// atomic maps, like the label selectors, are owned as a whole
+				if !owned.Members.Has(pe) {
+					return false
+				}
+				continue
+			}
+			if !matchesFields(child, d) {
+				return false
+			}
+		case []interface{}:
+			if !hasChild {
+				// atomic lists are owned as a whole
+				if !owned.Members.Has(pe) {
+					return false
+				}
+				continue
+			}
+			if !matchesElements(child, d) {
+				return false
+			}
+		default:
+			if !owned.Members.Has(pe) {
+				return false
+			}
+		}
+	}
+
+	// the fields that were applied before but aren't part of the desired object anymore
+	extra := false
+	notDesired := func(pe fieldpath.PathElement) {
+		if pe.FieldName == nil {
+			extra = true
+			return
+		}
+		if _, ok := desired[*pe.FieldName]; !ok {
+			extra = true
+		}
+	}
+	owned.Members.Iterate(notDesired)
+	owned.Children.Iterate(notDesired)
+
+	return !extra
+}
+
+// matchesElements checks whether the owned elements of an associative list are exactly the desired elements. Elements
+// are identified by their key fields for lists of maps, and by their value for lists of scalars.
+func matchesElements(owned *fieldpath.Set, desired []interface{}) bool {
+	matched := fieldpath.MakePathElementSet(len(desired))
+	for _, item := range desired {
+		if d, ok := item.(map[string]interface{}); ok {
+			pe, found := elementKey(owned, d)
+			if !found {
+				return false
+			}
+			child, ok := owned.Children.Get(pe)
+			if !ok {
+				child = &fieldpath.Set{}
+			}
+			if !matchesFields(child, d) {
+				return false
+			}
+			matched.Insert(pe)
+			continue
+		}
+
+		v := value.NewValueInterface(item)
+		pe := fieldpath.PathElement{Value: &v}
+		if !owned.Members.Has(pe) {
+			return false
+		}
+		matched.Insert(pe)
+	}
+
+	elements := fieldpath.MakePathElementSet(owned.Members.Size() + owned.Children.Size())
+	owned.Members.Iterate(elements.Insert)
+	owned.Children.Iterate(elements.Insert)
+
+	return elements.Equals(&matched)
+}
+
+// elementKey finds the owned element with key fields matching the desired element. Key fields absent from the desired
+// element, like a protocol left to its default value, match any value.
+func elementKey(owned *fieldpath.Set, desired map[string]interface{}) (fieldpath.PathElement, bool) {
+	var res fieldpath.PathElement
+	found := false
+	matches := func(pe fieldpath.PathElement) {
+		if found || pe.Key == nil {
+			return
+		}
+		present := 0
+		for _, f := range *pe.Key {
+			v, ok := desired[f.Name]
+			if !ok {
+				continue
+			}
+			if !value.Equals(value.NewValueInterface(v), f.Value) {
+				return
+			}
+			present++
+		}
+		if present > 0 {
+			res, found = pe, true
+		}
+	}
+	owned.Members.Iterate(matches)
+	owned.Children.Iterate(matches)
+
+	return res, found
+}
+
+// isEmptyValue returns whether the value is null, or an empty map or list.
+func isEmptyValue(v interface{}) bool {
+	switch d := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(d) == 0
+	case []interface{}:
+		return len(d) == 0
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// the fields recorded by the API server when applying the service returned by ownedService
+const ownedServiceFields = `{
+	"f:metadata": {
+		"f:labels": {".": {}, "f:app": {}, "f:tier": {}},
+		"f:ownerReferences": {"k:{\"uid\":\"abc\"}": {".": {}, "f:apiVersion": {}, "f:kind": {}, "f:name": {}, "f:uid": {}}}
+	},
+	"f:spec": {
+		"f:ports": {
+			"k:{\"port\":4317,\"protocol\":\"TCP\"}": {".": {}, "f:name": {}, "f:port": {}, "f:targetPort": {}}
+		},
+		"f:selector": {".": {}, "f:app": {}},
+		"f:externalIPs": {}
+	}
+}`
+
+func TestOwnsDesiredFields(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		change   func(svc *corev1.Service)
+		expected bool
+	}{
+		{
+			"same fields",
+			func(svc *corev1.Service) {},
+			true,
+		},
+		{
+			"different values are compared separately",
+			func(svc *corev1.Service) { svc.Labels["tier"] = "backend" },
+			true,
+		},
+		{
+			"removed label",
+			func(svc *corev1.Service) { delete(svc.Labels, "tier") },
+			false,
+		},
+		{
+			"added label",
+			func(svc *corev1.Service) { svc.Labels["new"] = "true" },
+			false,
+		},
+		{
+			"removed selector",
+			func(svc *corev1.Service) { svc.Spec.Selector = nil },
+			false,
+		},
+		{
+			"removed port",
+			func(svc *corev1.Service) { svc.Spec.Ports = nil },
+			false,
+		},
+		{
+			"added port",
+			func(svc *corev1.Service) {
+				svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{Name: "otlp-http", Port: 4318})
+			},
+			false,
+		},
+		{
+			"changed port key",
+			func(svc *corev1.Service) { svc.Spec.Ports[0].Port = 4318 },
+			false,
+		},
+		{
+			"removed field from a list element",
+			func(svc *corev1.Service) { svc.Spec.Ports[0].Name = "" },
+			false,
+		},
+		{
+			"atomic list with different elements",
+			func(svc *corev1.Service) { svc.Spec.ExternalIPs = []string{"10.0.0.2"} },
+			true,
+		},
+		{
+			"removed atomic list",
+			func(svc *corev1.Service) { svc.Spec.ExternalIPs = nil },
+			false,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			existing := ownedService()
+			existing.ManagedFields = []metav1.ManagedFieldsEntry{{
+				Manager:   FieldManager,
+				Operation: metav1.ManagedFieldsOperationApply,
+				FieldsV1:  &metav1.FieldsV1{Raw: []byte(ownedServiceFields)},
+			}}
+			desired := ownedService()
+			tt.change(desired)
+
+			// test
+			owned, err := ownsDesiredFields(desired, existing)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, owned)
+		})
+	}
+}
+
+func TestOwnsDesiredFieldsWithoutApplyRecord(t *testing.T) {
+	// prepare
+	existing := ownedService()
+	existing.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Manager:   FieldManager,
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(ownedServiceFields)},
+	}}
+
+	// test
+	owned, err := ownsDesiredFields(ownedService(), existing)
+
+	// verify
+	require.NoError(t, err)
+	assert.False(t, owned)
+}
+
+func ownedService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test-collector",
+			Namespace:       "default",
+			Labels:          map[string]string{"app": "collector", "tier": "frontend"},
+			OwnerReferences: []metav1.OwnerReference{{Name: "test", UID: "abc"}},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports:       []corev1.ServicePort{{Name: "otlp-grpc", Port: 4317, TargetPort: intstr.FromInt(4317)}},
+			Selector:    map[string]string{"app": "collector"},
+			ExternalIPs: []string{"10.0.0.1"},
+		},
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
+)
+
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+
+// serviceCAAnnotation asks OpenShift's service CA operator to issue a certificate for a service, stored in the
+// secret with the given name.
+const serviceCAAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+var routes = ownedKind{
+	name: "routes",
+	list: func() client.ObjectList {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(routeGVK.GroupVersion().WithKind("RouteList"))
+		return list
+	},
+	desired: desiredRoutes,
+	available: func(params Params) bool {
+		return params.Config.Platform() == platform.OpenShift
+	},
+}
+
+// Routes reconciles the OpenShift route(s) exposing the receivers of the instance.
+func Routes(ctx context.Context, params Params) error {
+	return reconcileOwned(ctx, params, routes)
+}
+
+// desiredRoutes returns one route per receiver port, when the routes are enabled for the instance.
+func desiredRoutes(_ context.Context, params Params) []client.Object {
+	desired := []client.Object{}
+	if params.Instance.Spec.Route == nil || params.Instance.Spec.Mode == v1alpha1.ModeSidecar {
+		return desired
+	}
+
+	ports, err := receiverPorts(params)
+	if err != nil {
+		params.Log.Error(err, "couldn't build the routes for this instance")
+		return desired
+	}
+
+	termination := params.Instance.Spec.Route.Termination
+	if len(termination) == 0 {
+		termination = v1alpha1.TLSRouteTerminationTypeEdge
+	}
+
+	for _, port := range ports {
+		name := naming.Route(params.Instance, port.Name)
+		labels := collector.Labels(params.Instance)
+		labels["app.kubernetes.io/name"] = name
+
+		route := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"to": map[string]interface{}{
+					"kind": "Service",
+					"name": naming.Service(params.Instance),
+				},
+				"port": map[string]interface{}{
+					"targetPort": port.Name,
+				},
+				"tls": map[string]interface{}{
+					"termination":                   string(termination),
+					"insecureEdgeTerminationPolicy": "Redirect",
+				},
+				"wildcardPolicy": "None",
+			},
+		}}
+		route.SetGroupVersionKind(routeGVK)
+		route.SetName(name)
+		route.SetNamespace(params.Instance.Namespace)
+		route.SetLabels(labels)
+		route.SetAnnotations(params.Instance.Annotations)
+
+		desired = append(desired, route)
+	}
+
+	return desired
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
+)
+
+func TestDesiredRoutes(t *testing.T) {
+	t.Run("should return one route per receiver port", func(t *testing.T) {
+		// prepare
+		p := params()
+		p.Instance.Spec.Route = &v1alpha1.RouteSpec{Termination: v1alpha1.TLSRouteTerminationTypePassthrough}
+
+		// test
+		actual := desiredRoutes(context.Background(), p)
+
+		// verify
+		require.Len(t, actual, 1)
+		route := actual[0].(*unstructured.Unstructured)
+		assert.Equal(t, "Route", route.GetKind())
+		assert.Equal(t, "test-collector-jaeger-grpc", route.GetName())
+
+		service, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name")
+		assert.Equal(t, "test-collector", service)
+		port, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort")
+		assert.Equal(t, "jaeger-grpc", port)
+		termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+		assert.Equal(t, "passthrough", termination)
+	})
+
+	t.Run("should not return routes when they're not enabled", func(t *testing.T) {
+		// test
+		actual := desiredRoutes(context.Background(), params())
+
+		// verify
+		assert.Empty(t, actual)
+	})
+
+	t.Run("should not return routes for sidecars", func(t *testing.T) {
+		// prepare
+		p := params()
+		p.Instance.Spec.Mode = v1alpha1.ModeSidecar
+		p.Instance.Spec.Route = &v1alpha1.RouteSpec{}
+
+		// test
+		actual := desiredRoutes(context.Background(), p)
+
+		// verify
+		assert.Empty(t, actual)
+	})
+}
+
+func TestRoutesOnlyOnOpenShift(t *testing.T) {
+	for _, tt := range []struct {
+		platform platform.Platform
+		expected bool
+	}{
+		{platform.OpenShift, true},
+		{platform.Kubernetes, false},
+		{platform.Unknown, false},
+	} {
+		t.Run(tt.platform.String(), func(t *testing.T) {
+			// prepare
+			p := params()
+			p.Config = config.New(config.WithPlatform(tt.platform))
+
+			// test
+			available := routes.isAvailable(p)
+
+			// verify
+			assert.Equal(t, tt.expected, available)
+		})
+	}
+}
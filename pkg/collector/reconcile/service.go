@@ -20,11 +20,8 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
@@ -34,31 +31,32 @@ import (
 
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 
+var services = ownedKind{
+	name:    "services",
+	list:    func() client.ObjectList { return &corev1.ServiceList{} },
+	desired: desiredServices,
+}
+
 // Services reconciles the service(s) required for the instance in the current context.
 func Services(ctx context.Context, params Params) error {
-	desired := []corev1.Service{}
+	return reconcileOwned(ctx, params, services)
+}
+
+// desiredServices returns the service(s) required for the instance in the current context.
+func desiredServices(ctx context.Context, params Params) []client.Object {
+	desired := []client.Object{}
 	if params.Instance.Spec.Mode != v1alpha1.ModeSidecar {
 		type builder func(context.Context, Params) *corev1.Service
 		for _, builder := range []builder{desiredService, headless, monitoringService} {
 			svc := builder(ctx, params)
 			// add only the non-nil to the list
 			if svc != nil {
-				desired = append(desired, *svc)
+				desired = append(desired, svc)
 			}
 		}
 	}
 
-	// first, handle the create/update parts
-	if err := expectedServices(ctx, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the expected services: %v", err)
-	}
-
-	// then, delete the extra objects
-	if err := deleteServices(ctx, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the services to be deleted: %v", err)
-	}
-
-	return nil
+	return desired
 }
 
 func desiredService(ctx context.Context, params Params) *corev1.Service {
@@ -69,13 +67,7 @@ func desiredService(ctx context.Context, params Params) *corev1.Service {
 	// whereas 'labels' refers to the service
 	selector := labels
 
-	config, err := adapters.ConfigFromString(params.Instance.Spec.Config)
-	if err != nil {
-		params.Log.Error(err, "couldn't extract the configuration from the context")
-		return nil
-	}
-
-	ports, err := adapters.ConfigToReceiverPorts(params.Log, config)
+	ports, err := receiverPorts(params)
 	if err != nil {
 		params.Log.Error(err, "couldn't build the service for this instance")
 		return nil
@@ -106,29 +98,69 @@ func desiredService(ctx context.Context, params Params) *corev1.Service {
 		return nil
 	}
 
+	annotations := params.Instance.Annotations
+	if params.Instance.Spec.TLS != nil && tlsIssuer(params) == v1alpha1.TLSIssuerServiceCA {
+		// new map, so that we don't touch the instance's annotations
+		annotations = map[string]string{serviceCAAnnotation: naming.TLSSecret(params.Instance)}
+		for k, v := range params.Instance.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	var internalTrafficPolicy *corev1.ServiceInternalTrafficPolicyType
+	if params.Instance.Spec.Mode == v1alpha1.ModeDaemonSet {
+		// the clients talk to the agent on their own node
+		local := corev1.ServiceInternalTrafficPolicyLocal
+		internalTrafficPolicy = &local
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        naming.Service(params.Instance),
 			Namespace:   params.Instance.Namespace,
 			Labels:      labels,
-			Annotations: params.Instance.Annotations,
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
-			Selector:  selector,
-			ClusterIP: "",
-			Ports:     ports,
+			Selector:              selector,
+			ClusterIP:             "",
+			Ports:                 ports,
+			InternalTrafficPolicy: internalTrafficPolicy,
 		},
 	}
 }
 
+// receiverPorts returns the ports of the receivers from the instance's configuration.
+func receiverPorts(params Params) ([]corev1.ServicePort, error) {
+	config, err := adapters.ConfigFromString(params.Instance.Spec.Config)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't extract the configuration from the context: %w", err)
+	}
+
+	return adapters.ConfigToReceiverPortsForVersion(params.Log, config, collector.TargetVersion(params.Instance))
+}
+
 func headless(ctx context.Context, params Params) *corev1.Service {
 	h := desiredService(ctx, params)
 	if h == nil {
 		return nil
 	}
 
+	if _, ok := h.Annotations[serviceCAAnnotation]; ok {
+		// the certificate from the service CA is only issued for the main service
+		annotations := map[string]string{}
+		for k, v := range h.Annotations {
+			if k != serviceCAAnnotation {
+				annotations[k] = v
+			}
+		}
+		h.Annotations = annotations
+	}
+
 	h.Name = naming.HeadlessService(params.Instance)
 	h.Spec.ClusterIP = "None"
+	// the headless service resolves to all the pods, the traffic policy doesn't apply
+	h.Spec.InternalTrafficPolicy = nil
 	return h
 }
 
@@ -151,96 +183,12 @@ func monitoringService(ctx context.Context, params Params) *corev1.Service {
 			ClusterIP: "",
 			Ports: []corev1.ServicePort{{
 				Name: "monitoring",
-				Port: 8888,
+				Port: collector.MetricsPort(params.Instance),
 			}},
 		},
 	}
 }
 
-func expectedServices(ctx context.Context, params Params, expected []corev1.Service) error {
-	for _, obj := range expected {
-		desired := obj
-
-		if err := controllerutil.SetControllerReference(&params.Instance, &desired, params.Scheme); err != nil {
-			return fmt.Errorf("failed to set controller reference: %w", err)
-		}
-
-		existing := &corev1.Service{}
-		nns := types.NamespacedName{Namespace: desired.Namespace, Name: desired.Name}
-		err := params.Client.Get(ctx, nns, existing)
-		if err != nil && k8serrors.IsNotFound(err) {
-			if err := params.Client.Create(ctx, &desired); err != nil {
-				return fmt.Errorf("failed to create: %w", err)
-			}
-			params.Log.V(2).Info("created", "service.name", desired.Name, "service.namespace", desired.Namespace)
-			continue
-		} else if err != nil {
-			return fmt.Errorf("failed to get: %w", err)
-		}
-
-		// it exists already, merge the two if the end result isn't identical to the existing one
-		updated := existing.DeepCopy()
-		if updated.Annotations == nil {
-			updated.Annotations = map[string]string{}
-		}
-		if updated.Labels == nil {
-			updated.Labels = map[string]string{}
-		}
-		updated.ObjectMeta.OwnerReferences = desired.ObjectMeta.OwnerReferences
-
-		for k, v := range desired.ObjectMeta.Annotations {
-			updated.ObjectMeta.Annotations[k] = v
-		}
-		for k, v := range desired.ObjectMeta.Labels {
-			updated.ObjectMeta.Labels[k] = v
-		}
-		updated.Spec.Ports = desired.Spec.Ports
-
-		patch := client.MergeFrom(existing)
-
-		if err := params.Client.Patch(ctx, updated, patch); err != nil {
-			return fmt.Errorf("failed to apply changes: %w", err)
-		}
-
-		params.Log.V(2).Info("applied", "service.name", desired.Name, "service.namespace", desired.Namespace)
-	}
-
-	return nil
-}
-
-func deleteServices(ctx context.Context, params Params, expected []corev1.Service) error {
-	opts := []client.ListOption{
-		client.InNamespace(params.Instance.Namespace),
-		client.MatchingLabels(map[string]string{
-			"app.kubernetes.io/instance":   fmt.Sprintf("%s.%s", params.Instance.Namespace, params.Instance.Name),
-			"app.kubernetes.io/managed-by": "opentelemetry-operator",
-		}),
-	}
-	list := &corev1.ServiceList{}
-	if err := params.Client.List(ctx, list, opts...); err != nil {
-		return fmt.Errorf("failed to list: %w", err)
-	}
-
-	for i := range list.Items {
-		existing := list.Items[i]
-		del := true
-		for _, keep := range expected {
-			if keep.Name == existing.Name && keep.Namespace == existing.Namespace {
-				del = false
-			}
-		}
-
-		if del {
-			if err := params.Client.Delete(ctx, &existing); err != nil {
-				return fmt.Errorf("failed to delete: %w", err)
-			}
-			params.Log.V(2).Info("deleted", "service.name", existing.Name, "service.namespace", existing.Namespace)
-		}
-	}
-
-	return nil
-}
-
 func filterPort(logger logr.Logger, candidate corev1.ServicePort, portNumbers map[int32]bool, portNames map[string]bool) *corev1.ServicePort {
 	if portNumbers[candidate.Port] {
 		return nil
@@ -19,14 +19,17 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/config"
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
 )
 
 func TestExtractPortNumbersAndNames(t *testing.T) {
@@ -132,7 +135,8 @@ func TestDesiredService(t *testing.T) {
 
 func TestExpectedServices(t *testing.T) {
 	t.Run("should create the service", func(t *testing.T) {
-		err := expectedServices(context.Background(), params(), []v1.Service{service("test-collector", params().Instance.Spec.Ports)})
+		desired := service("test-collector", params().Instance.Spec.Ports)
+		err := applyOwned(context.Background(), params(), services, []client.Object{&desired})
 		assert.NoError(t, err)
 
 		exists, err := populateObjectIfExists(t, &v1.Service{}, types.NamespacedName{Namespace: "default", Name: "test-collector"})
@@ -153,7 +157,8 @@ func TestExpectedServices(t *testing.T) {
 		}
 
 		ports := append(params().Instance.Spec.Ports, extraPorts)
-		err := expectedServices(context.Background(), params(), []v1.Service{service("test-collector", ports)})
+		desired := service("test-collector", ports)
+		err := applyOwned(context.Background(), params(), services, []client.Object{&desired})
 		assert.NoError(t, err)
 
 		actual := v1.Service{}
@@ -181,7 +186,7 @@ func TestDeleteServices(t *testing.T) {
 		assert.True(t, exists)
 
 		desired := desiredService(context.Background(), params())
-		err = deleteServices(context.Background(), params(), []v1.Service{*desired})
+		err = pruneOwned(context.Background(), params(), services, []client.Object{desired})
 		assert.NoError(t, err)
 
 		exists, err = populateObjectIfExists(t, &v1.Service{}, types.NamespacedName{Namespace: "default", Name: "delete-service-collector"})
@@ -198,6 +203,39 @@ func TestHeadlessService(t *testing.T) {
 	})
 }
 
+func TestDaemonSetServiceIsNodeLocal(t *testing.T) {
+	// prepare
+	p := params()
+	p.Instance.Spec.Mode = v1alpha1.ModeDaemonSet
+
+	// test
+	svc := desiredService(context.Background(), p)
+	h := headless(context.Background(), p)
+	deploymentSvc := desiredService(context.Background(), params())
+
+	// verify
+	require.NotNil(t, svc.Spec.InternalTrafficPolicy)
+	assert.Equal(t, v1.ServiceInternalTrafficPolicyLocal, *svc.Spec.InternalTrafficPolicy)
+	assert.Nil(t, h.Spec.InternalTrafficPolicy)
+	assert.Nil(t, deploymentSvc.Spec.InternalTrafficPolicy)
+}
+
+func TestServiceCAAnnotation(t *testing.T) {
+	// prepare
+	p := params()
+	p.Config = config.New(config.WithPlatform(platform.OpenShift))
+	p.Instance.Spec.TLS = &v1alpha1.TLSSpec{Issuer: v1alpha1.TLSIssuerAuto}
+
+	// test
+	svc := desiredService(context.Background(), p)
+	h := headless(context.Background(), p)
+
+	// verify
+	assert.Equal(t, "test-collector-tls", svc.Annotations["service.beta.openshift.io/serving-cert-secret-name"])
+	assert.NotContains(t, h.Annotations, "service.beta.openshift.io/serving-cert-secret-name")
+	assert.Empty(t, p.Instance.Annotations)
+}
+
 func TestMonitoringService(t *testing.T) {
 	t.Run("returned service should expose monitoring port", func(t *testing.T) {
 		expected := []v1.ServicePort{{
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
 )
@@ -32,7 +33,7 @@ func TestExpectedStatefulsets(t *testing.T) {
 	expectedSs := collector.StatefulSet(param.Config, logger, param.Instance)
 
 	t.Run("should create StatefulSet", func(t *testing.T) {
-		err := expectedStatefulSets(context.Background(), param, []v1.StatefulSet{expectedSs})
+		err := applyOwned(context.Background(), param, statefulSets, []client.Object{&expectedSs})
 		assert.NoError(t, err)
 
 		actual := v1.StatefulSet{}
@@ -45,7 +46,7 @@ func TestExpectedStatefulsets(t *testing.T) {
 	})
 	t.Run("should update statefulset", func(t *testing.T) {
 		createObjectIfNotExists(t, "test-collector", &expectedSs)
-		err := expectedStatefulSets(context.Background(), param, []v1.StatefulSet{expectedSs})
+		err := applyOwned(context.Background(), param, statefulSets, []client.Object{&expectedSs})
 		assert.NoError(t, err)
 
 		actual := v1.StatefulSet{}
@@ -85,7 +86,7 @@ func TestExpectedStatefulsets(t *testing.T) {
 
 		createObjectIfNotExists(t, "dummy", &ds)
 
-		err := deleteStatefulSets(context.Background(), param, []v1.StatefulSet{expectedSs})
+		err := pruneOwned(context.Background(), param, statefulSets, []client.Object{&expectedSs})
 		assert.NoError(t, err)
 
 		actual := v1.StatefulSet{}
@@ -123,7 +124,7 @@ func TestExpectedStatefulsets(t *testing.T) {
 
 		createObjectIfNotExists(t, "dummy", &ds)
 
-		err := deleteStatefulSets(context.Background(), param, []v1.StatefulSet{expectedSs})
+		err := pruneOwned(context.Background(), param, statefulSets, []client.Object{&expectedSs})
 		assert.NoError(t, err)
 
 		actual := v1.StatefulSet{}
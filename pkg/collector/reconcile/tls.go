@@ -0,0 +1,417 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/certificate"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;certificates,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// caValidity is the validity of the certificate authorities generated by the operator. They're renewed along with
+	// the serving certificates when they expire within the renewal period of the serving certificates.
+	caValidity = 5 * 365 * 24 * time.Hour
+
+	// the defaults of the webhook, for instances created while it wasn't running
+	defaultTLSDuration    = 90 * 24 * time.Hour
+	defaultTLSRenewBefore = 30 * 24 * time.Hour
+
+	// minTLSRenewalRequeue is the shortest delay before reconciling an instance again to renew its certificates
+	minTLSRenewalRequeue = time.Minute
+)
+
+var (
+	issuerGVK      = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Issuer"}
+	certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+)
+
+// TLSCertificates reconciles the certificates used by the receivers of the instance, issued by the operator,
+// cert-manager or OpenShift's service CA, and records the current serving certificate in the status of the instance.
+func TLSCertificates(ctx context.Context, params Params) error {
+	current := params.Instance.Status.TLS
+	if params.Instance.Spec.TLS == nil {
+		if current == nil {
+			// nothing was issued for this instance
+			return nil
+		}
+		if err := removeCertManagerObjects(ctx, params); err != nil {
+			return err
+		}
+		if err := removeTLSSecrets(ctx, params); err != nil {
+			return err
+		}
+		return patchTLSStatus(ctx, params, nil)
+	}
+
+	issuer := tlsIssuer(params)
+	if current != nil && len(current.Issuer) > 0 && current.Issuer != issuer {
+		// the previous issuer would otherwise keep on replacing the certificates, or prevent the new one from
+		// issuing them
+		params.Log.V(1).Info("the issuer of the serving certificate has changed", "previous", current.Issuer, "issuer", issuer)
+		if current.Issuer == v1alpha1.TLSIssuerCertManager {
+			if err := removeCertManagerObjects(ctx, params); err != nil {
+				return err
+			}
+		}
+		if err := removeTLSSecrets(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	switch issuer {
+	case v1alpha1.TLSIssuerCertManager:
+		if err := applyCertManagerObjects(ctx, params); err != nil {
+			return err
+		}
+	case v1alpha1.TLSIssuerServiceCA:
+		// the certificate is requested with an annotation on the service, see desiredService
+	default:
+		if err := applyOperatorCertificates(ctx, params); err != nil {
+			return err
+		}
+	}
+
+	secret := &corev1.Secret{}
+	nsn := types.NamespacedName{Namespace: params.Instance.Namespace, Name: naming.TLSSecret(params.Instance)}
+	if err := params.Client.Get(ctx, nsn, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// the certificate hasn't been issued yet: the secret being created, or the service being annotated by the
+			// service CA, triggers a new reconciliation
+			params.Log.V(1).Info("the serving certificate isn't available yet", "secret", nsn.Name)
+			return patchTLSStatus(ctx, params, &v1alpha1.TLSStatus{Issuer: issuer})
+		}
+		return fmt.Errorf("failed to get the serving certificate: %w", err)
+	}
+
+	status := &v1alpha1.TLSStatus{
+		Issuer:      issuer,
+		Fingerprint: certificate.Fingerprint(secret.Data[corev1.TLSCertKey]),
+	}
+	if cert, err := certificate.Parse(secret.Data[corev1.TLSCertKey]); err == nil {
+		status.NotAfter = &metav1.Time{Time: cert.NotAfter}
+	}
+
+	if current != nil && len(current.Fingerprint) > 0 && current.Fingerprint != status.Fingerprint {
+		params.Recorder.Event(&params.Instance, "Normal", "CertificateRenewed", fmt.Sprintf("The serving certificate %s/%s has been renewed, restarting the pods", nsn.Namespace, nsn.Name))
+	}
+
+	return patchTLSStatus(ctx, params, status)
+}
+
+// TLSRenewal returns how long until the certificates issued by the operator for the instance have to be renewed, so
+// that the instance can be reconciled again at that time instead of relying on the resync period. It returns false
+// when the operator doesn't issue the certificates of the instance, as cert-manager and the service CA renew theirs.
+func TLSRenewal(ctx context.Context, params Params) (time.Duration, bool, error) {
+	if params.Instance.Spec.TLS == nil || tlsIssuer(params) != v1alpha1.TLSIssuerOperator {
+		return 0, false, nil
+	}
+
+	_, renewBefore := tlsDurations(params.Instance.Spec.TLS)
+	var renewal *time.Time
+	for _, name := range []string{naming.CASecret(params.Instance), naming.TLSSecret(params.Instance)} {
+		secret, err := getSecret(ctx, params, name)
+		if err != nil {
+			return 0, false, err
+		}
+		if secret == nil {
+			continue
+		}
+		cert, err := certificate.Parse(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			continue
+		}
+		at := cert.NotAfter.Add(-renewBefore)
+		if renewal == nil || at.Before(*renewal) {
+			renewal = &at
+		}
+	}
+
+	if renewal == nil {
+		return 0, false, nil
+	}
+
+	after := time.Until(*renewal)
+	if after < minTLSRenewalRequeue {
+		// the certificates are renewed by the reconciliation, which is retried with a backoff when it fails
+		after = minTLSRenewalRequeue
+	}
+	return after, true, nil
+}
+
+// tlsIssuer returns who issues the certificates for the instance.
+func tlsIssuer(params Params) v1alpha1.TLSIssuer {
+	issuer := params.Instance.Spec.TLS.Issuer
+	if issuer == v1alpha1.TLSIssuerAuto || len(issuer) == 0 {
+		if params.Config.Platform() == platform.OpenShift {
+			return v1alpha1.TLSIssuerServiceCA
+		}
+		if params.Config.Capabilities().CertManager {
+			return v1alpha1.TLSIssuerCertManager
+		}
+		return v1alpha1.TLSIssuerOperator
+	}
+	return issuer
+}
+
+// applyOperatorCertificates ensures that the instance has a certificate authority and a serving certificate signed
+// by it, covering the instance's DNS names and not expiring within the renewal period. When the certificate authority
+// is renewed, the previous one stays in the ca.crt bundle until it expires, so that the clients with the previous
+// bundle keep on trusting the receivers until they get the new one.
+func applyOperatorCertificates(ctx context.Context, params Params) error {
+	duration, renewBefore := tlsDurations(params.Instance.Spec.TLS)
+	dnsNames := collector.TLSDNSNames(params.Instance)
+
+	caSecret, err := getSecret(ctx, params, naming.CASecret(params.Instance))
+	if err != nil {
+		return err
+	}
+
+	ca := certificate.KeyPair{}
+	var bundle []byte
+	if caSecret != nil {
+		ca.Certificate = caSecret.Data[corev1.TLSCertKey]
+		ca.Key = caSecret.Data[corev1.TLSPrivateKeyKey]
+		bundle = caSecret.Data[corev1.ServiceAccountRootCAKey]
+		if len(bundle) == 0 {
+			// created before the bundle was kept along with the certificate authority
+			bundle = ca.Certificate
+		}
+	}
+	if caSecret == nil || len(ca.Key) == 0 || certificate.NeedsRenewal(ca.Certificate, renewBefore, nil, nil) {
+		commonName := fmt.Sprintf("%s.%s", params.Instance.Name, params.Instance.Namespace)
+		if ca, err = certificate.NewCA(commonName, caValidity); err != nil {
+			return fmt.Errorf("failed to generate the certificate authority: %w", err)
+		}
+		bundle = append(append([]byte{}, ca.Certificate...), bundle...)
+		params.Log.V(1).Info("generated a new certificate authority", "secret", naming.CASecret(params.Instance))
+	}
+
+	// the previous certificate authorities are dropped once they've expired
+	bundle = certificate.Unexpired(bundle)
+	if caSecret == nil || !bytes.Equal(caSecret.Data[corev1.TLSCertKey], ca.Certificate) || !bytes.Equal(caSecret.Data[corev1.ServiceAccountRootCAKey], bundle) {
+		if err := applySecret(ctx, params, naming.CASecret(params.Instance), map[string][]byte{
+			corev1.TLSCertKey:              ca.Certificate,
+			corev1.TLSPrivateKeyKey:        ca.Key,
+			corev1.ServiceAccountRootCAKey: bundle,
+		}); err != nil {
+			return err
+		}
+	}
+
+	servingSecret, err := getSecret(ctx, params, naming.TLSSecret(params.Instance))
+	if err != nil {
+		return err
+	}
+
+	serving := certificate.KeyPair{}
+	if servingSecret != nil {
+		serving.Certificate = servingSecret.Data[corev1.TLSCertKey]
+		serving.Key = servingSecret.Data[corev1.TLSPrivateKeyKey]
+	}
+	if servingSecret == nil || certificate.NeedsRenewal(serving.Certificate, renewBefore, dnsNames, ca.Certificate) {
+		if serving, err = certificate.NewServing(ca, dnsNames, duration); err != nil {
+			return fmt.Errorf("failed to generate the serving certificate: %w", err)
+		}
+		params.Log.V(1).Info("generated a new serving certificate", "secret", naming.TLSSecret(params.Instance))
+	} else if bytes.Equal(servingSecret.Data[corev1.ServiceAccountRootCAKey], bundle) {
+		return nil
+	}
+
+	return applySecret(ctx, params, naming.TLSSecret(params.Instance), map[string][]byte{
+		corev1.TLSCertKey:              serving.Certificate,
+		corev1.TLSPrivateKeyKey:        serving.Key,
+		corev1.ServiceAccountRootCAKey: bundle,
+	})
+}
+
+// tlsDurations returns the validity and the renewal period of the serving certificates.
+func tlsDurations(spec *v1alpha1.TLSSpec) (time.Duration, time.Duration) {
+	duration, renewBefore := defaultTLSDuration, defaultTLSRenewBefore
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	if spec.RenewBefore != nil {
+		renewBefore = spec.RenewBefore.Duration
+	}
+	return duration, renewBefore
+}
+
+func getSecret(ctx context.Context, params Params, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := params.Client.Get(ctx, types.NamespacedName{Namespace: params.Instance.Namespace, Name: name}, secret)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the secret %s: %w", name, err)
+	}
+	return secret, nil
+}
+
+func applySecret(ctx context.Context, params Params, name string, data map[string][]byte) error {
+	labels := collector.Labels(params.Instance)
+	labels["app.kubernetes.io/name"] = name
+
+	desired, _, err := prepare(params, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.Instance.Namespace,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := apply(ctx, params, desired); err != nil {
+		return fmt.Errorf("failed to apply the secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeTLSSecrets deletes the secrets with the instance's certificates.
+func removeTLSSecrets(ctx context.Context, params Params) error {
+	for _, name := range []string{naming.TLSSecret(params.Instance), naming.CASecret(params.Instance)} {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: params.Instance.Namespace}}
+		if err := params.Client.Delete(ctx, secret); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the secret %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// certManagerObjects returns the cert-manager objects issuing the instance's certificates: a self-signed issuer for
+// the certificate authority of the instance, and the issuer of the serving certificate using this authority.
+func certManagerObjects(otelcol v1alpha1.OpenTelemetryCollector) []*unstructured.Unstructured {
+	duration, renewBefore := tlsDurations(otelcol.Spec.TLS)
+
+	// the secrets issued by cert-manager aren't owned by the instance: their labels tell to which one they belong
+	secretLabels := map[string]interface{}{}
+	for k, v := range collector.SelectorLabels(otelcol) {
+		secretLabels[k] = v
+	}
+
+	dnsNames := []interface{}{}
+	for _, name := range collector.TLSDNSNames(otelcol) {
+		dnsNames = append(dnsNames, name)
+	}
+
+	selfSigned := newCertManagerObject(otelcol, issuerGVK, naming.SelfSignedIssuer(otelcol), map[string]interface{}{
+		"selfSigned": map[string]interface{}{},
+	})
+	ca := newCertManagerObject(otelcol, certificateGVK, naming.CASecret(otelcol), map[string]interface{}{
+		"isCA":           true,
+		"commonName":     fmt.Sprintf("%s.%s", otelcol.Name, otelcol.Namespace),
+		"secretName":     naming.CASecret(otelcol),
+		"secretTemplate": map[string]interface{}{"labels": secretLabels},
+		"duration":       caValidity.String(),
+		"privateKey":     map[string]interface{}{"algorithm": "ECDSA", "size": int64(256)},
+		"issuerRef":      map[string]interface{}{"name": naming.SelfSignedIssuer(otelcol), "kind": issuerGVK.Kind},
+	})
+	caIssuer := newCertManagerObject(otelcol, issuerGVK, naming.CAIssuer(otelcol), map[string]interface{}{
+		"ca": map[string]interface{}{"secretName": naming.CASecret(otelcol)},
+	})
+	serving := newCertManagerObject(otelcol, certificateGVK, naming.TLSSecret(otelcol), map[string]interface{}{
+		"secretName":     naming.TLSSecret(otelcol),
+		"secretTemplate": map[string]interface{}{"labels": secretLabels},
+		"dnsNames":       dnsNames,
+		"duration":       duration.String(),
+		"renewBefore":    renewBefore.String(),
+		"privateKey":     map[string]interface{}{"algorithm": "ECDSA", "size": int64(256)},
+		"issuerRef":      map[string]interface{}{"name": naming.CAIssuer(otelcol), "kind": issuerGVK.Kind},
+	})
+
+	return []*unstructured.Unstructured{selfSigned, ca, caIssuer, serving}
+}
+
+func newCertManagerObject(otelcol v1alpha1.OpenTelemetryCollector, gvk schema.GroupVersionKind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	labels := collector.Labels(otelcol)
+	labels["app.kubernetes.io/name"] = name
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+	obj.SetNamespace(otelcol.Namespace)
+	obj.SetLabels(labels)
+	return obj
+}
+
+// applyCertManagerObjects asks cert-manager to issue the instance's certificates.
+func applyCertManagerObjects(ctx context.Context, params Params) error {
+	for _, obj := range certManagerObjects(params.Instance) {
+		desired, gvk, err := prepare(params, obj)
+		if err != nil {
+			return err
+		}
+		if err := apply(ctx, params, desired); err != nil {
+			return fmt.Errorf("failed to apply the %s %s: %w", gvk.Kind, desired.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// removeCertManagerObjects deletes the cert-manager objects issuing the instance's certificates. There's nothing to
+// delete when cert-manager isn't installed.
+func removeCertManagerObjects(ctx context.Context, params Params) error {
+	otelcol := params.Instance
+	for _, obj := range []*unstructured.Unstructured{
+		newCertManagerObject(otelcol, issuerGVK, naming.SelfSignedIssuer(otelcol), nil),
+		newCertManagerObject(otelcol, certificateGVK, naming.CASecret(otelcol), nil),
+		newCertManagerObject(otelcol, issuerGVK, naming.CAIssuer(otelcol), nil),
+		newCertManagerObject(otelcol, certificateGVK, naming.TLSSecret(otelcol), nil),
+	} {
+		err := params.Client.Delete(ctx, obj)
+		if err != nil && !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete the %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func patchTLSStatus(ctx context.Context, params Params, status *v1alpha1.TLSStatus) error {
+	changed := params.Instance.DeepCopy()
+	changed.Status.TLS = status
+	if apiequality.Semantic.DeepEqual(params.Instance.Status.TLS, changed.Status.TLS) {
+		return nil
+	}
+
+	if err := params.Client.Status().Patch(ctx, changed, client.MergeFrom(&params.Instance)); err != nil {
+		return fmt.Errorf("failed to apply status changes to the OpenTelemetry CR: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/certificate"
+	"github.com/open-telemetry/opentelemetry-operator/internal/config"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/autodetect"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/platform"
+)
+
+func tlsParams() Params {
+	p := params()
+	p.Instance.Spec.TLS = &v1alpha1.TLSSpec{
+		Issuer:      v1alpha1.TLSIssuerOperator,
+		Duration:    &metav1.Duration{Duration: 90 * 24 * time.Hour},
+		RenewBefore: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+	}
+	return p
+}
+
+func TestOperatorCertificates(t *testing.T) {
+	t.Run("should generate the certificate authority and the serving certificate", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+
+		// test
+		err := applyOperatorCertificates(context.Background(), p)
+		require.NoError(t, err)
+
+		// verify
+		ca := corev1.Secret{}
+		exists, err := populateObjectIfExists(t, &ca, types.NamespacedName{Namespace: "default", Name: "test-collector-ca"})
+		require.NoError(t, err)
+		require.True(t, exists)
+		assert.Equal(t, instanceUID, ca.OwnerReferences[0].UID)
+
+		serving := corev1.Secret{}
+		exists, err = populateObjectIfExists(t, &serving, types.NamespacedName{Namespace: "default", Name: "test-collector-tls"})
+		require.NoError(t, err)
+		require.True(t, exists)
+		assert.Equal(t, corev1.SecretTypeTLS, serving.Type)
+		assert.Equal(t, ca.Data[corev1.TLSCertKey], serving.Data["ca.crt"])
+
+		cert, err := certificate.Parse(serving.Data[corev1.TLSCertKey])
+		require.NoError(t, err)
+		assert.Equal(t, collector.TLSDNSNames(p.Instance), cert.DNSNames)
+
+		pool := x509.NewCertPool()
+		require.True(t, pool.AppendCertsFromPEM(ca.Data[corev1.TLSCertKey]))
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: "test-collector.default.svc", Roots: pool})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should keep a valid serving certificate", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		before := corev1.Secret{}
+		_, err := populateObjectIfExists(t, &before, types.NamespacedName{Namespace: "default", Name: "test-collector-tls"})
+		require.NoError(t, err)
+
+		// test
+		err = applyOperatorCertificates(context.Background(), p)
+		require.NoError(t, err)
+
+		// verify
+		after := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &after, types.NamespacedName{Namespace: "default", Name: "test-collector-tls"})
+		require.NoError(t, err)
+		assert.Equal(t, before.Data, after.Data)
+	})
+
+	t.Run("should renew the serving certificate when the names change", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		p.Instance.Spec.TLS.AdditionalDNSNames = []string{"otel.example.com"}
+
+		// test
+		err := applyOperatorCertificates(context.Background(), p)
+		require.NoError(t, err)
+
+		// verify
+		serving := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &serving, types.NamespacedName{Namespace: "default", Name: "test-collector-tls"})
+		require.NoError(t, err)
+		cert, err := certificate.Parse(serving.Data[corev1.TLSCertKey])
+		require.NoError(t, err)
+		assert.Contains(t, cert.DNSNames, "otel.example.com")
+	})
+}
+
+func TestOperatorCertificatesRotation(t *testing.T) {
+	t.Run("should keep the previous certificate authority in the bundle until it expires", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		p.Instance.Name = "test-rotation"
+		previous, err := certificate.NewCA("previous-ca", 24*time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, applySecret(context.Background(), p, "test-rotation-collector-ca", map[string][]byte{
+			corev1.TLSCertKey:       previous.Certificate,
+			corev1.TLSPrivateKeyKey: previous.Key,
+		}))
+
+		// test
+		err = applyOperatorCertificates(context.Background(), p)
+		require.NoError(t, err)
+
+		// verify
+		ca := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &ca, types.NamespacedName{Namespace: "default", Name: "test-rotation-collector-ca"})
+		require.NoError(t, err)
+		assert.NotEqual(t, previous.Certificate, ca.Data[corev1.TLSCertKey])
+
+		serving := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &serving, types.NamespacedName{Namespace: "default", Name: "test-rotation-collector-tls"})
+		require.NoError(t, err)
+		bundle := append(append([]byte{}, ca.Data[corev1.TLSCertKey]...), previous.Certificate...)
+		assert.Equal(t, bundle, serving.Data["ca.crt"])
+		assert.Equal(t, bundle, ca.Data["ca.crt"])
+
+		cert, err := certificate.Parse(serving.Data[corev1.TLSCertKey])
+		require.NoError(t, err)
+		pool := x509.NewCertPool()
+		require.True(t, pool.AppendCertsFromPEM(ca.Data[corev1.TLSCertKey]))
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: "test-rotation-collector.default.svc", Roots: pool})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should drop the previous certificate authority once it has expired", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		p.Instance.Name = "test-rotation-expired"
+		current, err := certificate.NewCA("current-ca", 24*365*time.Hour)
+		require.NoError(t, err)
+		expired, err := certificate.NewCA("expired-ca", -time.Minute)
+		require.NoError(t, err)
+		bundle := append(append([]byte{}, current.Certificate...), expired.Certificate...)
+		require.NoError(t, applySecret(context.Background(), p, "test-rotation-expired-collector-ca", map[string][]byte{
+			corev1.TLSCertKey:       current.Certificate,
+			corev1.TLSPrivateKeyKey: current.Key,
+			"ca.crt":                bundle,
+		}))
+
+		// test
+		err = applyOperatorCertificates(context.Background(), p)
+		require.NoError(t, err)
+
+		// verify
+		ca := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &ca, types.NamespacedName{Namespace: "default", Name: "test-rotation-expired-collector-ca"})
+		require.NoError(t, err)
+		assert.Equal(t, current.Certificate, ca.Data[corev1.TLSCertKey])
+		assert.Equal(t, current.Certificate, ca.Data["ca.crt"])
+
+		serving := corev1.Secret{}
+		_, err = populateObjectIfExists(t, &serving, types.NamespacedName{Namespace: "default", Name: "test-rotation-expired-collector-tls"})
+		require.NoError(t, err)
+		assert.Equal(t, current.Certificate, serving.Data["ca.crt"])
+	})
+}
+
+func TestTLSCertificatesStatus(t *testing.T) {
+	// prepare
+	p := tlsParams()
+	p.Instance.Name = "test-tls"
+	instance := p.Instance
+	createObjectIfNotExists(t, "test-tls", &instance)
+	p.Instance = instance
+
+	// test
+	err := TLSCertificates(context.Background(), p)
+	require.NoError(t, err)
+
+	// verify
+	actual := v1alpha1.OpenTelemetryCollector{}
+	exists, err := populateObjectIfExists(t, &actual, types.NamespacedName{Namespace: "default", Name: "test-tls"})
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.NotNil(t, actual.Status.TLS)
+	assert.Equal(t, v1alpha1.TLSIssuerOperator, actual.Status.TLS.Issuer)
+	assert.NotEmpty(t, actual.Status.TLS.Fingerprint)
+	assert.NotNil(t, actual.Status.TLS.NotAfter)
+
+	// and once disabled, the secrets are removed
+	actual.Spec.TLS = nil
+	p.Instance = actual
+	err = TLSCertificates(context.Background(), p)
+	require.NoError(t, err)
+
+	exists, err = populateObjectIfExists(t, &corev1.Secret{}, types.NamespacedName{Namespace: "default", Name: "test-tls-collector-tls"})
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestTLSRenewal(t *testing.T) {
+	t.Run("should be scheduled before the certificates expire", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		p.Instance.Name = "test-renewal"
+		p.Instance.Spec.TLS.Duration = &metav1.Duration{Duration: 24 * time.Hour}
+		p.Instance.Spec.TLS.RenewBefore = &metav1.Duration{Duration: 8 * time.Hour}
+		require.NoError(t, applyOperatorCertificates(context.Background(), p))
+
+		// test
+		after, scheduled, err := TLSRenewal(context.Background(), p)
+
+		// verify
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.LessOrEqual(t, int64(after), int64(16*time.Hour))
+		assert.Greater(t, int64(after), int64(15*time.Hour))
+	})
+
+	t.Run("should not be scheduled for certificates issued by cert-manager", func(t *testing.T) {
+		// prepare
+		p := tlsParams()
+		p.Instance.Spec.TLS.Issuer = v1alpha1.TLSIssuerCertManager
+
+		// test
+		_, scheduled, err := TLSRenewal(context.Background(), p)
+
+		// verify
+		require.NoError(t, err)
+		assert.False(t, scheduled)
+	})
+}
+
+func TestTLSIssuer(t *testing.T) {
+	for _, tt := range []struct {
+		issuer      v1alpha1.TLSIssuer
+		certManager bool
+		expected    v1alpha1.TLSIssuer
+	}{
+		{v1alpha1.TLSIssuerAuto, false, v1alpha1.TLSIssuerOperator},
+		{v1alpha1.TLSIssuerAuto, true, v1alpha1.TLSIssuerCertManager},
+		{"", true, v1alpha1.TLSIssuerCertManager},
+		{v1alpha1.TLSIssuerOperator, true, v1alpha1.TLSIssuerOperator},
+		{v1alpha1.TLSIssuerCertManager, false, v1alpha1.TLSIssuerCertManager},
+	} {
+		t.Run(string(tt.issuer), func(t *testing.T) {
+			// prepare
+			p := Params{Config: config.New(config.WithAutoDetect(certManagerDetected(tt.certManager)))}
+			require.NoError(t, p.Config.AutoDetect())
+			p.Instance.Spec.TLS = &v1alpha1.TLSSpec{Issuer: tt.issuer}
+
+			// test
+			issuer := tlsIssuer(p)
+
+			// verify
+			assert.Equal(t, tt.expected, issuer)
+		})
+	}
+
+	t.Run("openshift", func(t *testing.T) {
+		// prepare
+		p := Params{Config: config.New(config.WithPlatform(platform.OpenShift))}
+		p.Instance.Spec.TLS = &v1alpha1.TLSSpec{Issuer: v1alpha1.TLSIssuerAuto}
+
+		// test
+		issuer := tlsIssuer(p)
+
+		// verify
+		assert.Equal(t, v1alpha1.TLSIssuerServiceCA, issuer)
+	})
+}
+
+func TestCertManagerObjects(t *testing.T) {
+	// prepare
+	otelcol := tlsParams().Instance
+
+	// test
+	objects := certManagerObjects(otelcol)
+
+	// verify
+	require.Len(t, objects, 4)
+	names := []string{}
+	for _, obj := range objects {
+		names = append(names, obj.GetKind()+"/"+obj.GetName())
+	}
+	assert.Equal(t, []string{"Issuer/test-collector-selfsigned", "Certificate/test-collector-ca", "Issuer/test-collector-ca", "Certificate/test-collector-tls"}, names)
+
+	serving := objects[3].Object["spec"].(map[string]interface{})
+	assert.Equal(t, "test-collector-tls", serving["secretName"])
+	assert.Equal(t, "720h0m0s", serving["renewBefore"])
+	assert.Len(t, serving["dnsNames"], 8)
+}
+
+type certManagerDetected bool
+
+func (d certManagerDetected) Platform() (platform.Platform, error) {
+	return platform.Kubernetes, nil
+}
+
+func (d certManagerDetected) Capabilities() (autodetect.Capabilities, error) {
+	return autodetect.Capabilities{CertManager: bool(d)}, nil
+}
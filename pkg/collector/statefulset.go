@@ -27,10 +27,10 @@ import (
 
 // StatefulSet builds the statefulset for the given instance.
 func StatefulSet(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector) appsv1.StatefulSet {
-	labels := Labels(otelcol)
+	labels := withDefaults(cfg.CollectorLabels(), Labels(otelcol))
 	labels["app.kubernetes.io/name"] = naming.Collector(otelcol)
 
-	annotations := Annotations(otelcol)
+	annotations := withDefaults(cfg.CollectorAnnotations(), Annotations(otelcol))
 
 	return appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -42,20 +42,22 @@ func StatefulSet(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTel
 		Spec: appsv1.StatefulSetSpec{
 			ServiceName: naming.Service(otelcol),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
+				MatchLabels: SelectorLabels(otelcol),
 			},
-			Template: corev1.PodTemplateSpec{
+			Template: withPodTemplateOverlay(logger, otelcol, corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      labels,
-					Annotations: otelcol.Annotations,
+					Annotations: withDefaults(cfg.CollectorAnnotations(), otelcol.Annotations),
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccountName(otelcol),
-					Containers:         []corev1.Container{Container(cfg, logger, otelcol)},
+					ImagePullSecrets:   ImagePullSecrets(cfg, otelcol),
+					InitContainers:     InitContainers(cfg, otelcol),
+					Containers:         append([]corev1.Container{Container(cfg, logger, otelcol)}, AdditionalContainers(cfg, otelcol)...),
 					Volumes:            Volumes(cfg, otelcol),
 					Tolerations:        otelcol.Spec.Tolerations,
 				},
-			},
+			}),
 			Replicas:             otelcol.Spec.Replicas,
 			PodManagementPolicy:  "Parallel",
 			VolumeClaimTemplates: VolumeClaimTemplates(cfg, otelcol),
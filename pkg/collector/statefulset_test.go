@@ -57,8 +57,11 @@ func TestStatefulSetNewDefault(t *testing.T) {
 	// none of the default annotations should propagate down to the pod
 	assert.Empty(t, ss.Spec.Template.Annotations)
 
-	// the pod selector should match the pod spec's labels
-	assert.Equal(t, ss.Spec.Selector.MatchLabels, ss.Spec.Template.Labels)
+	// the pod selector should be the stable subset of the pod spec's labels
+	assert.Equal(t, SelectorLabels(otelcol), ss.Spec.Selector.MatchLabels)
+	for k, v := range ss.Spec.Selector.MatchLabels {
+		assert.Equal(t, v, ss.Spec.Template.Labels[k])
+	}
 
 	// assert correct service name
 	assert.Equal(t, "my-instance-collector", ss.Spec.ServiceName)
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/naming"
+)
+
+const (
+	// TLSMountPath is where the receivers' certificate is mounted in the collector's container.
+	TLSMountPath = "/tls"
+
+	// AnnotationTLSFingerprint is set on the pods with the fingerprint of the receivers' certificate, so that they're
+	// restarted when the certificate is renewed.
+	AnnotationTLSFingerprint = "opentelemetry.io/tls-certificate-sha256"
+)
+
+// the receivers' settings were named 'tls_settings' before this version
+var tlsKeyRenamed = semver.MustParse("0.36.0")
+
+// tlsProtocols lists, per type of receiver, the protocols accepting TLS settings. An empty protocol means that the
+// settings are at the root of the receiver's configuration.
+var tlsProtocols = map[string][]string{
+	"otlp":       {"grpc", "http"},
+	"jaeger":     {"grpc", "thrift_http"},
+	"zipkin":     {""},
+	"opencensus": {""},
+}
+
+// TLSDNSNames returns the names the receivers' certificate is valid for: the names of the instance's services, in
+// all the forms they can be resolved within the cluster, and the additional names from the spec.
+func TLSDNSNames(otelcol v1alpha1.OpenTelemetryCollector) []string {
+	names := []string{}
+	for _, svc := range []string{naming.Service(otelcol), naming.HeadlessService(otelcol)} {
+		names = append(names,
+			svc,
+			fmt.Sprintf("%s.%s", svc, otelcol.Namespace),
+			fmt.Sprintf("%s.%s.svc", svc, otelcol.Namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", svc, otelcol.Namespace),
+		)
+	}
+	if otelcol.Spec.TLS != nil {
+		names = append(names, otelcol.Spec.TLS.AdditionalDNSNames...)
+	}
+	return names
+}
+
+// Config returns the configuration the collector runs with: the one from the spec, with the TLS settings pointing
+// to the mounted certificate added to the receivers when the operator manages their certificates. Receivers which
+// already have TLS settings are left untouched, as is a configuration that can't be parsed, which is reported by
+// the other tasks.
+func Config(otelcol v1alpha1.OpenTelemetryCollector) string {
+	if otelcol.Spec.TLS == nil {
+		return otelcol.Spec.Config
+	}
+
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(otelcol.Spec.Config), doc); err != nil || len(doc.Content) == 0 {
+		return otelcol.Spec.Config
+	}
+
+	key := "tls"
+	if v := TargetVersion(otelcol); v != nil && v.LessThan(tlsKeyRenamed) {
+		key = "tls_settings"
+	}
+
+	receivers := lookupMapping(doc.Content[0], "receivers")
+	if receivers == nil {
+		return otelcol.Spec.Config
+	}
+
+	changed := false
+	for i := 0; i+1 < len(receivers.Content); i += 2 {
+		name := receivers.Content[i].Value
+		if !tlsEnabled(otelcol.Spec.TLS, name) {
+			continue
+		}
+
+		receiverType := strings.SplitN(name, "/", 2)[0]
+		for _, protocol := range tlsProtocols[receiverType] {
+			var settings *yaml.Node
+			if len(protocol) == 0 {
+				settings = ensureMapping(receivers, i+1)
+			} else if protocols := lookupMapping(receivers.Content[i+1], "protocols"); protocols != nil {
+				if idx := keyIndex(protocols, protocol); idx >= 0 {
+					settings = ensureMapping(protocols, idx+1)
+				}
+			}
+
+			if settings == nil || keyIndex(settings, "tls") >= 0 || keyIndex(settings, "tls_settings") >= 0 {
+				continue
+			}
+
+			settings.Content = append(settings.Content, scalarNode(key), &yaml.Node{
+				Kind: yaml.MappingNode,
+				Tag:  "!!map",
+				Content: []*yaml.Node{
+					scalarNode("cert_file"), scalarNode(fmt.Sprintf("%s/%s", TLSMountPath, corev1.TLSCertKey)),
+					scalarNode("key_file"), scalarNode(fmt.Sprintf("%s/%s", TLSMountPath, corev1.TLSPrivateKeyKey)),
+				},
+			})
+			changed = true
+		}
+	}
+
+	if !changed {
+		return otelcol.Spec.Config
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return otelcol.Spec.Config
+	}
+	if err := encoder.Close(); err != nil {
+		return otelcol.Spec.Config
+	}
+	return buf.String()
+}
+
+// tlsEnabled returns whether the receiver with the given name gets the TLS settings.
+func tlsEnabled(spec *v1alpha1.TLSSpec, receiver string) bool {
+	if len(spec.Receivers) == 0 {
+		return true
+	}
+	for _, r := range spec.Receivers {
+		if r == receiver {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSVolume returns the volume with the receivers' certificate.
+func TLSVolume(otelcol v1alpha1.OpenTelemetryCollector) corev1.Volume {
+	return corev1.Volume{
+		Name: naming.TLSVolume(),
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: naming.TLSSecret(otelcol),
+			},
+		},
+	}
+}
+
+func keyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func lookupMapping(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	idx := keyIndex(mapping, key)
+	if idx < 0 || mapping.Content[idx+1].Kind != yaml.MappingNode {
+		return nil
+	}
+	return mapping.Content[idx+1]
+}
+
+// ensureMapping returns the mapping at the given index of the parent's content, replacing an empty value, like
+// the one of 'grpc:', by an empty mapping. It returns nil when the value is something else.
+func ensureMapping(parent *yaml.Node, idx int) *yaml.Node {
+	value := parent.Content[idx]
+	switch {
+	case value.Kind == yaml.MappingNode:
+		return value
+	case value.Kind == yaml.ScalarNode && (value.Tag == "!!null" || len(value.Value) == 0):
+		mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		parent.Content[idx] = mapping
+		return mapping
+	}
+	return nil
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestConfigWithTLS(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		version  string
+		tls      *v1alpha1.TLSSpec
+		config   string
+		expected string
+	}{
+		{
+			desc: "disabled",
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+`,
+			expected: `receivers:
+  otlp:
+    protocols:
+      grpc:
+`,
+		},
+		{
+			desc: "all receivers",
+			tls:  &v1alpha1.TLSSpec{},
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+      http:
+        endpoint: 0.0.0.0:4318 # the default
+  zipkin:
+  prometheus:
+    config: {}
+exporters:
+  logging:
+`,
+			expected: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        tls:
+          cert_file: /tls/tls.crt
+          key_file: /tls/tls.key
+      http:
+        endpoint: 0.0.0.0:4318 # the default
+        tls:
+          cert_file: /tls/tls.crt
+          key_file: /tls/tls.key
+  zipkin:
+    tls:
+      cert_file: /tls/tls.crt
+      key_file: /tls/tls.key
+  prometheus:
+    config: {}
+exporters:
+  logging:
+`,
+		},
+		{
+			desc: "selected receivers",
+			tls:  &v1alpha1.TLSSpec{Receivers: []string{"jaeger/secure"}},
+			config: `receivers:
+  jaeger:
+    protocols:
+      grpc:
+  jaeger/secure:
+    protocols:
+      grpc:
+      thrift_compact:
+`,
+			expected: `receivers:
+  jaeger:
+    protocols:
+      grpc:
+  jaeger/secure:
+    protocols:
+      grpc:
+        tls:
+          cert_file: /tls/tls.crt
+          key_file: /tls/tls.key
+      thrift_compact:
+`,
+		},
+		{
+			desc: "existing settings",
+			tls:  &v1alpha1.TLSSpec{},
+			config: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        tls:
+          cert_file: /certs/tls.crt
+          key_file: /certs/tls.key
+`,
+			expected: `receivers:
+  otlp:
+    protocols:
+      grpc:
+        tls:
+          cert_file: /certs/tls.crt
+          key_file: /certs/tls.key
+`,
+		},
+		{
+			desc:    "before the settings were renamed",
+			version: "0.29.0",
+			tls:     &v1alpha1.TLSSpec{},
+			config: `receivers:
+  opencensus:
+`,
+			expected: `receivers:
+  opencensus:
+    tls_settings:
+      cert_file: /tls/tls.crt
+      key_file: /tls/tls.key
+`,
+		},
+		{
+			desc:     "invalid configuration",
+			tls:      &v1alpha1.TLSSpec{},
+			config:   "receivers: [",
+			expected: "receivers: [",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Config: tt.config,
+					TLS:    tt.tls,
+				},
+			}
+			otelcol.Status.Version = tt.version
+			if len(tt.version) == 0 {
+				otelcol.Status.Version = "0.36.0"
+			}
+
+			// test
+			config := Config(otelcol)
+
+			// verify
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestTLSDNSNames(t *testing.T) {
+	// prepare
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			TLS: &v1alpha1.TLSSpec{AdditionalDNSNames: []string{"otel.example.com"}},
+		},
+	}
+	otelcol.Name = "my-instance"
+	otelcol.Namespace = "observability"
+
+	// test
+	names := TLSDNSNames(otelcol)
+
+	// verify
+	assert.Equal(t, []string{
+		"my-instance-collector",
+		"my-instance-collector.observability",
+		"my-instance-collector.observability.svc",
+		"my-instance-collector.observability.svc.cluster.local",
+		"my-instance-collector-headless",
+		"my-instance-collector-headless.observability",
+		"my-instance-collector-headless.observability.svc",
+		"my-instance-collector-headless.observability.svc.cluster.local",
+		"otel.example.com",
+	}, names)
+}
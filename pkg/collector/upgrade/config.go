@@ -0,0 +1,522 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configEditor changes a collector configuration in place: only the lines of the entries that are changed are
+// rewritten, so that the rest of the configuration, including comments, blank lines, key order, anchors and
+// quoting, stays byte for byte the same.
+type configEditor struct {
+	original string
+	lines    []string
+	doc      *yaml.Node
+	root     *yaml.Node
+	parents  map[*yaml.Node]*yaml.Node
+	values   map[*yaml.Node]*yaml.Node
+	removed  map[*yaml.Node][]*yaml.Node
+	edits    []lineEdit
+
+	// rewrite is set when a change can't be done in place, like in a flow mapping at the top level, in which case
+	// the whole configuration is rendered again.
+	rewrite bool
+}
+
+// lineEdit replaces the lines [start, end) of the original configuration. When key is set, the lines are replaced
+// by the entry with this key, as it is in the configuration tree when the configuration is rendered.
+type lineEdit struct {
+	start, end int
+	indent     int
+	key, value *yaml.Node
+	seq        int
+}
+
+func newConfigEditor(config string) (*configEditor, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(config), doc); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	content := config
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	lines := strings.SplitAfter(content, "\n")
+	lines = lines[:len(lines)-1]
+
+	e := &configEditor{
+		original: config,
+		lines:    lines,
+		doc:      doc,
+		parents:  map[*yaml.Node]*yaml.Node{},
+		values:   map[*yaml.Node]*yaml.Node{},
+		removed:  map[*yaml.Node][]*yaml.Node{},
+	}
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		e.root = doc.Content[0]
+	}
+	e.index(doc)
+	return e, nil
+}
+
+func (e *configEditor) index(node *yaml.Node) {
+	for i, child := range node.Content {
+		e.parents[child] = node
+		if node.Kind == yaml.MappingNode && i%2 == 1 {
+			e.values[node.Content[i-1]] = child
+		}
+		e.index(child)
+	}
+}
+
+// mapping returns the mapping under the given key, or nil when the key doesn't exist or isn't a mapping.
+func mapping(parent *yaml.Node, key string) *yaml.Node {
+	value := lookup(parent, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		return nil
+	}
+	return value
+}
+
+// lookup returns the value for the given key in the mapping, resolving aliases, or nil when the key doesn't exist.
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return resolve(mapping.Content[i+1])
+		}
+	}
+	return nil
+}
+
+// lookupPath returns the value at the given path of keys, starting at the given mapping, or nil when there's none.
+func lookupPath(mapping *yaml.Node, path []string) *yaml.Node {
+	value := mapping
+	for _, key := range path {
+		if value = lookup(value, key); value == nil {
+			return nil
+		}
+	}
+	return value
+}
+
+// resolve returns the node an alias points to, or the node itself when it's not an alias.
+func resolve(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias
+	}
+	return node
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// mappingNode returns a mapping with the given keys and values, alternated.
+func mappingNode(keysAndValues ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: keysAndValues}
+}
+
+// remove deletes the entry with the given key from the mapping, along with the comments right above it.
+func (e *configEditor) remove(mapping *yaml.Node, key string) {
+	idx := keyIndex(mapping, key)
+	if idx < 0 {
+		return
+	}
+	k, v := mapping.Content[idx], mapping.Content[idx+1]
+	mapping.Content = append(mapping.Content[:idx:idx], mapping.Content[idx+2:]...)
+	e.removed[mapping] = append(e.removed[mapping], k)
+	e.forget(k)
+
+	if !e.inPlace(mapping, k) {
+		e.changed(e.enclosingEntry(mapping))
+		return
+	}
+
+	start, end := e.entryRange(k, v)
+	indent := k.Column - 1
+	for start > 0 && isComment(e.lines[start-1]) && indentation(e.lines[start-1]) == indent {
+		start--
+	}
+	e.add(lineEdit{start: start, end: end, indent: indent})
+}
+
+// set sets the value for the given key, replacing the value of the existing entry or adding a new entry to the end
+// of the mapping.
+func (e *configEditor) set(mapping *yaml.Node, key string, value *yaml.Node) {
+	if keyIndex(mapping, key) >= 0 {
+		e.rename(mapping, key, key, value)
+		return
+	}
+
+	k := scalarNode(key)
+	mapping.Content = append(mapping.Content, k, value)
+	e.adopt(mapping, k, value)
+
+	// the new entry goes after the last one of the original configuration, including the removed ones
+	var last, first *yaml.Node
+	for _, existing := range append(keys(mapping), e.removed[mapping]...) {
+		if existing.Line == 0 {
+			continue
+		}
+		if first == nil || existing.Line < first.Line {
+			first = existing
+		}
+		if last == nil || existing.Line > last.Line {
+			last = existing
+		}
+	}
+	if first == nil || !e.inPlace(mapping, first) || !e.inPlace(mapping, last) {
+		e.changed(e.enclosingEntry(mapping))
+		return
+	}
+
+	_, end := e.entryRange(last, e.values[last])
+	e.add(lineEdit{start: end, end: end, indent: first.Column - 1, key: k, value: value})
+}
+
+// rename replaces the entry with the given key by an entry with the new key and value, at the same position. The
+// comment next to a scalar value is kept.
+func (e *configEditor) rename(mapping *yaml.Node, key, newKey string, value *yaml.Node) {
+	idx := keyIndex(mapping, key)
+	if idx < 0 {
+		return
+	}
+	k := mapping.Content[idx]
+	k.Value = newKey
+	k.Style = 0
+	if old := mapping.Content[idx+1]; old.Kind == yaml.ScalarNode && value.Kind == yaml.ScalarNode && len(value.LineComment) == 0 {
+		// keep the comment next to the value
+		value.LineComment = old.LineComment
+	}
+	mapping.Content[idx+1] = value
+	e.adopt(mapping, value)
+	e.changed(mapping, k)
+}
+
+// removeItem deletes the item at the given index from the sequence.
+func (e *configEditor) removeItem(seq *yaml.Node, idx int) {
+	seq.Content = append(seq.Content[:idx:idx], seq.Content[idx+1:]...)
+	e.changed(e.enclosingEntry(seq))
+}
+
+// appendItem adds an item to the end of the sequence.
+func (e *configEditor) appendItem(seq *yaml.Node, value *yaml.Node) {
+	seq.Content = append(seq.Content, value)
+	e.adopt(seq, value)
+	e.changed(e.enclosingEntry(seq))
+}
+
+// setItem replaces the item at the given index of the sequence.
+func (e *configEditor) setItem(seq *yaml.Node, idx int, value *yaml.Node) {
+	value.Style = seq.Content[idx].Style
+	seq.Content[idx] = value
+	e.adopt(seq, value)
+	e.changed(e.enclosingEntry(seq))
+}
+
+// ensureMapping returns the mapping under the given key, creating it when the key doesn't exist or has an empty
+// value. It returns nil when the key has a value that isn't a mapping.
+func (e *configEditor) ensureMapping(parent *yaml.Node, key string) *yaml.Node {
+	value := lookup(parent, key)
+	switch {
+	case value == nil:
+		value = mappingNode()
+		e.set(parent, key, value)
+	case value.Kind == yaml.ScalarNode && (value.Tag == "!!null" || len(value.Value) == 0):
+		value = mappingNode()
+		e.rename(parent, key, key, value)
+	case value.Kind != yaml.MappingNode:
+		return nil
+	}
+	return value
+}
+
+// adopt registers new nodes as children of the given parent, so that changes made to them later on are found.
+func (e *configEditor) adopt(parent *yaml.Node, nodes ...*yaml.Node) {
+	for _, node := range nodes {
+		e.parents[node] = parent
+		e.index(node)
+	}
+}
+
+// changed records that the entry with the given key has to be rendered again. When the entry can't be replaced in
+// place, the closest enclosing entry that can be is rendered again instead.
+func (e *configEditor) changed(mapping, key *yaml.Node) {
+	for mapping != nil {
+		if key.Line == 0 {
+			// new entries are rendered along with the changes they're part of
+			return
+		}
+		if e.inPlace(mapping, key) {
+			for _, edit := range e.edits {
+				if edit.key == key {
+					return
+				}
+			}
+			start, end := e.entryRange(key, e.values[key])
+			e.add(lineEdit{start: start, end: end, indent: key.Column - 1, key: key})
+			return
+		}
+		mapping, key = e.enclosingEntry(mapping)
+	}
+	e.rewrite = true
+}
+
+func (e *configEditor) add(edit lineEdit) {
+	edit.seq = len(e.edits)
+	e.edits = append(e.edits, edit)
+}
+
+// forget drops the pending edits for an entry that has been removed.
+func (e *configEditor) forget(key *yaml.Node) {
+	edits := e.edits[:0]
+	for _, edit := range e.edits {
+		if edit.key != key {
+			edits = append(edits, edit)
+		}
+	}
+	e.edits = edits
+}
+
+// inPlace returns whether the entry with the given key can be changed by replacing its lines, which is the case
+// for entries of block mappings starting on their own line.
+func (e *configEditor) inPlace(mapping, key *yaml.Node) bool {
+	if mapping.Style&yaml.FlowStyle != 0 || key.Line == 0 || key.Line > len(e.lines) {
+		return false
+	}
+	line := e.lines[key.Line-1]
+	return key.Column-1 <= len(line) && len(strings.TrimSpace(line[:key.Column-1])) == 0
+}
+
+// enclosingEntry returns the mapping and the key of the entry containing the given node.
+func (e *configEditor) enclosingEntry(node *yaml.Node) (*yaml.Node, *yaml.Node) {
+	for {
+		parent, ok := e.parents[node]
+		if !ok {
+			return nil, nil
+		}
+		if parent.Kind == yaml.MappingNode {
+			for i := 1; i < len(parent.Content); i += 2 {
+				if parent.Content[i] == node {
+					return parent, parent.Content[i-1]
+				}
+			}
+		}
+		node = parent
+	}
+}
+
+// currentValue returns the value of the entry with the given key, as changed so far.
+func (e *configEditor) currentValue(key *yaml.Node) *yaml.Node {
+	mapping := e.parents[key]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return e.values[key]
+}
+
+// entryRange returns the lines [start, end) of the original configuration holding the entry with the given key:
+// the line of the key and the following ones that are more indented, or, for sequences, the items at the same
+// indentation. Trailing blank lines and comments less indented than the key aren't part of the entry.
+func (e *configEditor) entryRange(key, value *yaml.Node) (int, int) {
+	indent := key.Column - 1
+	start := key.Line - 1
+	end := start + 1
+	for i := start + 1; i < len(e.lines); i++ {
+		line := e.lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case len(trimmed) == 0:
+			continue
+		case indentation(line) > indent:
+			end = i + 1
+		case isComment(line):
+			continue
+		case indentation(line) == indent && resolve(value).Kind == yaml.SequenceNode && strings.HasPrefix(trimmed, "-"):
+			end = i + 1
+		default:
+			return start, end
+		}
+	}
+	return start, end
+}
+
+// modified returns whether any change has been made to the configuration.
+func (e *configEditor) modified() bool {
+	return e.rewrite || len(e.edits) > 0
+}
+
+// String returns the configuration with the changes applied.
+func (e *configEditor) String() (string, error) {
+	if !e.modified() {
+		return e.original, nil
+	}
+
+	if e.rewrite {
+		return encode(e.doc)
+	}
+
+	var edits []lineEdit
+	for _, edit := range e.edits {
+		if !e.nested(edit) {
+			edits = append(edits, edit)
+		}
+	}
+
+	// apply the edits from the bottom up, so that the line numbers of the pending ones stay valid
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start > edits[j].start
+		}
+		if (edits[i].start == edits[i].end) != (edits[j].start == edits[j].end) {
+			return edits[i].start != edits[i].end
+		}
+		return edits[i].seq > edits[j].seq
+	})
+
+	lines := append([]string{}, e.lines...)
+	for _, edit := range edits {
+		var replacement []string
+		if edit.key != nil {
+			value := edit.value
+			if value == nil {
+				value = e.currentValue(edit.key)
+			}
+			rendered, err := renderEntry(edit.key, value, edit.indent)
+			if err != nil {
+				return "", err
+			}
+			replacement = rendered
+		}
+		lines = append(lines[:edit.start], append(replacement, lines[edit.end:]...)...)
+	}
+
+	res := strings.Join(lines, "")
+	if !strings.HasSuffix(e.original, "\n") {
+		res = strings.TrimSuffix(res, "\n")
+	}
+	return res, nil
+}
+
+// nested returns whether the edit is covered by another one rendering a whole entry, which already reflects it.
+func (e *configEditor) nested(edit lineEdit) bool {
+	for _, other := range e.edits {
+		if other.seq == edit.seq || other.key == nil || other.start == other.end || other.value != nil {
+			continue
+		}
+		if edit.start < other.start || edit.end > other.end {
+			continue
+		}
+		if edit.start < other.end || edit.indent > other.indent {
+			return true
+		}
+	}
+	return false
+}
+
+// renderEntry renders a single mapping entry, indented by the given number of spaces.
+func renderEntry(key, value *yaml.Node, indent int) ([]string, error) {
+	// the comments above and below the entry aren't part of its lines, and are kept as they are
+	k := *key
+	k.HeadComment = ""
+	k.FootComment = ""
+	for node := value; node != nil; {
+		node.FootComment = ""
+		if len(node.Content) == 0 {
+			break
+		}
+		if node.Kind == yaml.MappingNode {
+			node.Content[len(node.Content)-2].FootComment = ""
+		}
+		node = node.Content[len(node.Content)-1]
+	}
+
+	rendered, err := encode(mappingNode(&k, value))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitAfter(rendered, "\n")
+	lines = lines[:len(lines)-1]
+	prefix := strings.Repeat(" ", indent)
+	for i, line := range lines {
+		if len(strings.TrimSpace(line)) > 0 {
+			lines[i] = prefix + line
+		}
+	}
+	return lines, nil
+}
+
+func encode(node *yaml.Node) (string, error) {
+	untagMergeKeys(node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", fmt.Errorf("failed to marshal back configuration: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to marshal back configuration: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// untagMergeKeys drops the tag of the merge keys, like in "<<: *defaults", which is otherwise rendered explicitly.
+func untagMergeKeys(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!merge" {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		untagMergeKeys(child)
+	}
+}
+
+func keyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func keys(mapping *yaml.Node) []*yaml.Node {
+	var res []*yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		res = append(res, mapping.Content[i])
+	}
+	return res
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
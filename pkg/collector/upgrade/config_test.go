@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConfigEditorKeepsUnchangedConfig(t *testing.T) {
+	// prepare
+	config := `receivers:   # odd spacing
+  otlp: {protocols: {grpc: }}
+`
+	cfg, err := newConfigEditor(config)
+	require.NoError(t, err)
+
+	// test
+	res, err := cfg.String()
+
+	// verify
+	require.NoError(t, err)
+	assert.False(t, cfg.modified())
+	assert.Equal(t, config, res)
+}
+
+func TestConfigEditorChanges(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		config   string
+		change   func(cfg *configEditor)
+		expected string
+	}{
+		{
+			desc: "remove with the comment above",
+			config: `processors:
+  batch:
+
+  # retries
+  queued_retry:
+    num_workers: 2
+    # more workers
+  other:
+`,
+			change: func(cfg *configEditor) {
+				cfg.remove(mapping(cfg.root, "processors"), "queued_retry")
+			},
+			expected: `processors:
+  batch:
+
+  other:
+`,
+		},
+		{
+			desc: "rename keeps the position and the comment",
+			config: `extensions:
+  health_check:
+    port: 13133 # default
+    path: "/"
+`,
+			change: func(cfg *configEditor) {
+				cfg.rename(mapping(mapping(cfg.root, "extensions"), "health_check"), "port", "endpoint", scalarNode("0.0.0.0:13133"))
+			},
+			expected: `extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133 # default
+    path: "/"
+`,
+		},
+		{
+			desc: "set appends after the last entry, including removed ones",
+			config: `processors:
+  resource:
+    # old style
+    type: some-type
+
+service:
+`,
+			change: func(cfg *configEditor) {
+				processor := mapping(mapping(cfg.root, "processors"), "resource")
+				cfg.set(processor, "attributes", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle})
+				cfg.remove(processor, "type")
+			},
+			expected: `processors:
+  resource:
+    attributes: []
+
+service:
+`,
+		},
+		{
+			desc:   "flow mappings are changed as a whole",
+			config: "extensions:\n  health_check: {port: 13133}\n  pprof: {}\n",
+			change: func(cfg *configEditor) {
+				cfg.rename(mapping(mapping(cfg.root, "extensions"), "health_check"), "port", "endpoint", scalarNode("localhost"))
+			},
+			expected: "extensions:\n  health_check: {endpoint: localhost}\n  pprof: {}\n",
+		},
+		{
+			desc:   "nested changes are rendered once",
+			config: "a:\n  b: {c: 1, d: 2}\n",
+			change: func(cfg *configEditor) {
+				b := mapping(mapping(cfg.root, "a"), "b")
+				cfg.remove(b, "c")
+				cfg.set(b, "e", scalarNode("3"))
+			},
+			expected: "a:\n  b: {d: 2, e: \"3\"}\n",
+		},
+		{
+			desc:   "top-level flow mapping",
+			config: "{exporters: {logging: {}}, processors: {batch: {}}}",
+			change: func(cfg *configEditor) {
+				cfg.remove(cfg.root, "processors")
+			},
+			expected: "{exporters: {logging: {}}}\n",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			cfg, err := newConfigEditor(tt.config)
+			require.NoError(t, err)
+
+			// test
+			tt.change(cfg)
+			res, err := cfg.String()
+
+			// verify
+			require.NoError(t, err)
+			assert.True(t, cfg.modified())
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/upgrade"
+)
+
+// TestUpgradeCorpus upgrades each configuration under testdata/corpus from the oldest version to the latest one, and
+// compares the result with the ".upgraded" file next to it, byte for byte. Configurations without such a file don't
+// need any change, and must be left as they are.
+func TestUpgradeCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "corpus", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, ".upgraded.yaml") {
+			continue
+		}
+
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			// prepare
+			config, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+
+			expected := config
+			upgradedFile := strings.TrimSuffix(file, ".yaml") + ".upgraded.yaml"
+			if _, err := os.Stat(upgradedFile); err == nil {
+				expected, err = ioutil.ReadFile(upgradedFile)
+				require.NoError(t, err)
+			}
+
+			existing := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+				Spec:       v1alpha1.OpenTelemetryCollectorSpec{Config: string(config)},
+			}
+			existing.Status.Version = "0.1.0"
+
+			// test
+			res, err := upgrade.Instance(logger, existing, semver.MustParse(upgrade.Latest.String()))
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), res.Spec.Config)
+
+			// upgrading again is a no-op
+			again, err := upgrade.Instance(logger, res, semver.MustParse(upgrade.Latest.String()))
+			require.NoError(t, err)
+			assert.Equal(t, res.Spec.Config, again.Spec.Config)
+		})
+	}
+}
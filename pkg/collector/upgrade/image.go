@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/go-logr/logr"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+// followsDefaultImage returns whether the image of the instance follows the operator's default image: either the
+// image isn't set at all, or it's the one recorded by the operator and the instance's upgrade strategy is automatic.
+func followsDefaultImage(otelcol v1alpha1.OpenTelemetryCollector) bool {
+	if len(otelcol.Spec.Image) == 0 {
+		return true
+	}
+
+	return otelcol.Spec.UpgradeStrategy != v1alpha1.UpgradeStrategyNone &&
+		otelcol.Spec.Image == otelcol.Annotations[v1alpha1.AnnotationDefaultImage]
+}
+
+// pinnedVersion returns the version of the image the instance is pinned to, or nil when the image follows the
+// operator's default image or has no version in its tag.
+func pinnedVersion(otelcol v1alpha1.OpenTelemetryCollector) *semver.Version {
+	if followsDefaultImage(otelcol) {
+		return nil
+	}
+	return collector.ImageVersion(otelcol.Spec.Image)
+}
+
+// followDefaultImage replaces the image recorded by the operator for the instance with the given default image, when
+// the instance follows the operator's default image and its configuration has been upgraded. Older images are
+// never recorded, so that the configuration remains compatible with the image.
+func followDefaultImage(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector, defaultImage string) v1alpha1.OpenTelemetryCollector {
+	if len(otelcol.Spec.Image) == 0 || len(defaultImage) == 0 || otelcol.Spec.Image == defaultImage || !followsDefaultImage(otelcol) {
+		return otelcol
+	}
+
+	if otelcol.Spec.UpgradePolicy == v1alpha1.UpgradePolicyNone || otelcol.Status.PendingUpgrade != nil {
+		// the configuration hasn't been upgraded, it might not be compatible with the new image
+		return otelcol
+	}
+
+	currentV, defaultV := collector.ImageVersion(otelcol.Spec.Image), collector.ImageVersion(defaultImage)
+	if currentV != nil && defaultV != nil && defaultV.LessThan(currentV) {
+		return otelcol
+	}
+
+	logger.Info("replacing the image of the OpenTelemetry Collector instance with the operator's default image", "name", otelcol.Name, "namespace", otelcol.Namespace, "image", otelcol.Spec.Image, "default", defaultImage)
+	upgraded := otelcol.DeepCopy()
+	upgraded.Spec.Image = defaultImage
+	upgraded.Annotations[v1alpha1.AnnotationDefaultImage] = defaultImage
+	return *upgraded
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+func TestFollowDefaultImage(t *testing.T) {
+	recorded := "otel/opentelemetry-collector:0.29.0"
+	for _, tt := range []struct {
+		desc     string
+		image    string
+		strategy v1alpha1.UpgradeStrategy
+		policy   v1alpha1.UpgradePolicy
+		pending  bool
+		def      string
+		expected string
+	}{
+		{"recorded image", recorded, v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyAutomatic, false, "otel/opentelemetry-collector:0.31.0", "otel/opentelemetry-collector:0.31.0"},
+		{"strategy none", recorded, v1alpha1.UpgradeStrategyNone, v1alpha1.UpgradePolicyAutomatic, false, "otel/opentelemetry-collector:0.31.0", recorded},
+		{"explicit image", "otel/opentelemetry-collector:0.28.0", v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyAutomatic, false, "otel/opentelemetry-collector:0.31.0", "otel/opentelemetry-collector:0.28.0"},
+		{"upgrade policy none", recorded, v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyNone, false, "otel/opentelemetry-collector:0.31.0", recorded},
+		{"pending upgrade", recorded, v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyManual, true, "otel/opentelemetry-collector:0.31.0", recorded},
+		{"older default image", recorded, v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyAutomatic, false, "otel/opentelemetry-collector:0.28.0", recorded},
+		{"no image", "", v1alpha1.UpgradeStrategyAutomatic, v1alpha1.UpgradePolicyAutomatic, false, "otel/opentelemetry-collector:0.31.0", ""},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{v1alpha1.AnnotationDefaultImage: recorded},
+				},
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Image:           tt.image,
+					UpgradeStrategy: tt.strategy,
+					UpgradePolicy:   tt.policy,
+				},
+			}
+			if tt.pending {
+				otelcol.Status.PendingUpgrade = &v1alpha1.PendingUpgrade{}
+			}
+
+			// test
+			res := followDefaultImage(logf.Log.WithName("unit-tests"), otelcol, tt.def)
+
+			// verify
+			assert.Equal(t, tt.expected, res.Spec.Image)
+			if tt.expected != tt.image {
+				assert.Equal(t, tt.expected, res.Annotations[v1alpha1.AnnotationDefaultImage])
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rule is a declarative change to the collector configuration, made by the upgrade to a version. It returns the
+// descriptions of the changes it made, if any.
+type rule interface {
+	apply(cfg *configEditor) ([]string, error)
+}
+
+// components selects the components of a kind by type, like the receivers of type "otlp", named either "otlp" or
+// "otlp/<name>".
+type components struct {
+	kind string
+	typ  string
+}
+
+func receivers(typ string) components  { return components{kind: "receivers", typ: typ} }
+func processors(typ string) components { return components{kind: "processors", typ: typ} }
+func exporters(typ string) components  { return components{kind: "exporters", typ: typ} }
+func extensions(typ string) components { return components{kind: "extensions", typ: typ} }
+
+// each calls fn for each selected component, with the mapping holding the components and the component's name.
+func (c components) each(cfg *configEditor, fn func(section *yaml.Node, name string) error) error {
+	section := mapping(cfg.root, c.kind)
+	if section == nil {
+		return nil
+	}
+	for _, k := range keys(section) {
+		if k.Value == c.typ || strings.HasPrefix(k.Value, c.typ+"/") {
+			if err := fn(section, k.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// eachMapping calls fn for each selected component that has its own settings, skipping the ones using the default
+// configuration.
+func (c components) eachMapping(cfg *configEditor, fn func(name string, component *yaml.Node) error) error {
+	return c.each(cfg, func(section *yaml.Node, name string) error {
+		switch component := lookup(section, name); component.Kind {
+		case yaml.MappingNode:
+			return fn(name, component)
+		case yaml.ScalarNode:
+			// this component is using the default configuration
+			return nil
+		default:
+			return fmt.Errorf("the %s %q is invalid (neither a string nor map)", c.singular(), name)
+		}
+	})
+}
+
+func (c components) singular() string {
+	return strings.TrimSuffix(c.kind, "s")
+}
+
+// renameKey renames a property of the selected components. Its value is kept, unless values is set, in which case
+// the value is translated with it.
+type renameKey struct {
+	components components
+	path       []string
+	to         string
+	values     map[string]string
+}
+
+func (r renameKey) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.eachMapping(cfg, func(name string, component *yaml.Node) error {
+		parent := lookupPath(component, r.path[:len(r.path)-1])
+		key := r.path[len(r.path)-1]
+		value := lookup(parent, key)
+		if value == nil {
+			return nil
+		}
+		if lookup(parent, r.to) != nil {
+			return fmt.Errorf("the %s %q has both the properties '%s' and '%s'", r.components.singular(), name, key, r.to)
+		}
+		if r.values != nil {
+			translated, ok := r.values[value.Value]
+			if !ok {
+				return fmt.Errorf("the value %q of the property '%s' of the %s %q is invalid", value.Value, key, r.components.singular(), name)
+			}
+			value = scalarNode(translated)
+		}
+		cfg.rename(parent, key, r.to, value)
+		changes = append(changes, fmt.Sprintf("renamed the property '%s' to '%s' for %s %q", strings.Join(r.path, "."), r.to, r.components.singular(), name))
+		return nil
+	})
+	return changes, err
+}
+
+// moveKey moves a property of the selected components to another place within the components.
+type moveKey struct {
+	components components
+	from       []string
+	to         []string
+}
+
+func (r moveKey) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.eachMapping(cfg, func(name string, component *yaml.Node) error {
+		parent := lookupPath(component, r.from[:len(r.from)-1])
+		key := r.from[len(r.from)-1]
+		value := lookup(parent, key)
+		if value == nil {
+			return nil
+		}
+
+		target := component
+		for _, k := range r.to[:len(r.to)-1] {
+			if target = cfg.ensureMapping(target, k); target == nil {
+				return fmt.Errorf("the %s %q can't hold the property '%s'", r.components.singular(), name, strings.Join(r.to, "."))
+			}
+		}
+		if lookup(target, r.to[len(r.to)-1]) != nil {
+			return fmt.Errorf("the %s %q has both the properties '%s' and '%s'", r.components.singular(), name, strings.Join(r.from, "."), strings.Join(r.to, "."))
+		}
+
+		cfg.remove(parent, key)
+		cfg.set(target, r.to[len(r.to)-1], value)
+		changes = append(changes, fmt.Sprintf("moved the property '%s' to '%s' for %s %q", strings.Join(r.from, "."), strings.Join(r.to, "."), r.components.singular(), name))
+		return nil
+	})
+	return changes, err
+}
+
+// deleteKey removes a property from the selected components.
+type deleteKey struct {
+	components components
+	path       []string
+}
+
+func (r deleteKey) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.eachMapping(cfg, func(name string, component *yaml.Node) error {
+		parent := lookupPath(component, r.path[:len(r.path)-1])
+		key := r.path[len(r.path)-1]
+		if lookup(parent, key) == nil {
+			return nil
+		}
+		cfg.remove(parent, key)
+		changes = append(changes, fmt.Sprintf("removed the property %s for %s %q", strings.Join(r.path, "."), r.components.singular(), name))
+		return nil
+	})
+	return changes, err
+}
+
+// deleteComponents removes the selected components, along with the references to them from the service.
+type deleteComponents struct {
+	components components
+}
+
+func (r deleteComponents) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.each(cfg, func(section *yaml.Node, name string) error {
+		cfg.remove(section, name)
+		changes = append(changes, fmt.Sprintf("removed the %s %q", r.components.singular(), name))
+
+		service := mapping(cfg.root, "service")
+		if r.components.kind == "extensions" {
+			if removeReference(cfg, lookup(service, "extensions"), name) {
+				changes = append(changes, fmt.Sprintf("removed the %s %q from the service", r.components.singular(), name))
+			}
+			return nil
+		}
+
+		pipelines := mapping(service, "pipelines")
+		if pipelines == nil {
+			return nil
+		}
+		for _, pipeline := range keys(pipelines) {
+			if removeReference(cfg, lookupPath(pipelines, []string{pipeline.Value, r.components.kind}), name) {
+				changes = append(changes, fmt.Sprintf("removed the %s %q from the pipeline %q", r.components.singular(), name, pipeline.Value))
+			}
+		}
+		return nil
+	})
+	return changes, err
+}
+
+// removeReference removes the given name from a list of components, returning whether it was there.
+func removeReference(cfg *configEditor, list *yaml.Node, name string) bool {
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return false
+	}
+	for i, item := range list.Content {
+		if item.Value == name {
+			cfg.removeItem(list, i)
+			return true
+		}
+	}
+	return false
+}
+
+// renameComponents changes the type of the selected components, keeping their names, along with the references to
+// them from the service.
+type renameComponents struct {
+	components components
+	to         string
+}
+
+func (r renameComponents) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.each(cfg, func(section *yaml.Node, name string) error {
+		renamed := r.to + strings.TrimPrefix(name, r.components.typ)
+		if err := renameComponent(cfg, r.components.kind, name, renamed); err != nil {
+			return err
+		}
+		changes = append(changes, fmt.Sprintf("renamed the %s %q to %q", r.components.singular(), name, renamed))
+		return nil
+	})
+	return changes, err
+}
+
+// renameComponent renames a component of the given kind, along with the references to it from the service.
+func renameComponent(cfg *configEditor, kind, name, renamed string) error {
+	section := mapping(cfg.root, kind)
+	if lookup(section, renamed) != nil {
+		return fmt.Errorf("the %s %q can't be renamed to %q, which already exists", strings.TrimSuffix(kind, "s"), name, renamed)
+	}
+	cfg.rename(section, name, renamed, section.Content[keyIndex(section, name)+1])
+
+	for _, list := range references(cfg, kind) {
+		for i, item := range list.Content {
+			if item.Value == name {
+				cfg.setItem(list, i, scalarNode(renamed))
+			}
+		}
+	}
+	return nil
+}
+
+// references returns the lists of components of the given kind from the service.
+func references(cfg *configEditor, kind string) []*yaml.Node {
+	service := mapping(cfg.root, "service")
+	var lists []*yaml.Node
+	if kind == "extensions" {
+		lists = append(lists, lookup(service, "extensions"))
+	} else if pipelines := mapping(service, "pipelines"); pipelines != nil {
+		for _, pipeline := range keys(pipelines) {
+			lists = append(lists, lookupPath(pipelines, []string{pipeline.Value, kind}))
+		}
+	}
+
+	var res []*yaml.Node
+	for _, list := range lists {
+		if list != nil && list.Kind == yaml.SequenceNode {
+			res = append(res, list)
+		}
+	}
+	return res
+}
+
+// portToEndpoint replaces a port property of the selected components by an endpoint property listening on the given
+// host and port.
+type portToEndpoint struct {
+	components components
+	path       []string
+	endpoint   string
+	host       string
+}
+
+func (r portToEndpoint) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.eachMapping(cfg, func(name string, component *yaml.Node) error {
+		parent := lookupPath(component, r.path[:len(r.path)-1])
+		key := r.path[len(r.path)-1]
+		port := lookup(parent, key)
+		if port == nil {
+			return nil
+		}
+		if port.Kind != yaml.ScalarNode {
+			return fmt.Errorf("the property '%s' of the %s %q isn't a port", key, r.components.singular(), name)
+		}
+		cfg.rename(parent, key, r.endpoint, scalarNode(fmt.Sprintf("%s:%s", r.host, port.Value)))
+		changes = append(changes, fmt.Sprintf("migrated the property '%s' to '%s' for %s %q", key, r.endpoint, r.components.singular(), name))
+		return nil
+	})
+	return changes, err
+}
+
+// setDefault sets a property of the selected components when it isn't set, so that they keep the behavior they had
+// before the default value changed. The properties holding it must exist, possibly empty, as they usually enable
+// features, like the protocols of a receiver. The value is parsed as YAML.
+type setDefault struct {
+	components components
+	path       []string
+	value      string
+}
+
+func (r setDefault) apply(cfg *configEditor) ([]string, error) {
+	var changes []string
+	err := r.components.each(cfg, func(section *yaml.Node, name string) error {
+		value := &yaml.Node{}
+		if err := yaml.Unmarshal([]byte(r.value), value); err != nil || len(value.Content) == 0 {
+			return fmt.Errorf("the default value %q for the property '%s' is invalid", r.value, strings.Join(r.path, "."))
+		}
+
+		parent := lookup(section, name)
+		for _, k := range r.path[:len(r.path)-1] {
+			if parent = lookup(parent, k); parent == nil {
+				// the property doesn't apply, like for a protocol that isn't enabled
+				return nil
+			}
+		}
+
+		target := cfg.ensureMapping(section, name)
+		for _, k := range r.path[:len(r.path)-1] {
+			if target == nil {
+				break
+			}
+			target = cfg.ensureMapping(target, k)
+		}
+		if target == nil {
+			return fmt.Errorf("the %s %q can't hold the property '%s'", r.components.singular(), name, strings.Join(r.path, "."))
+		}
+		if lookup(target, r.path[len(r.path)-1]) != nil {
+			return nil
+		}
+		cfg.set(target, r.path[len(r.path)-1], value.Content[0])
+		changes = append(changes, fmt.Sprintf("set the property '%s' to its former default %s for %s %q", strings.Join(r.path, "."), r.value, r.components.singular(), name))
+		return nil
+	})
+	return changes, err
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+// stepFixture is a test case for the upgrade to a version, read from testdata/steps/<version>/<case>.yaml.
+type stepFixture struct {
+	Config   string   `json:"config"`
+	Expected string   `json:"expected"`
+	Changes  []string `json:"changes"`
+}
+
+func TestVersionsFromFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "steps", "*", "*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name := filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+		t.Run(name, func(t *testing.T) {
+			// prepare
+			content, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			fixture := stepFixture{}
+			require.NoError(t, yaml.UnmarshalStrict(content, &fixture))
+
+			var step *otelcolVersion
+			for i := range versions {
+				if versions[i].String() == filepath.Base(filepath.Dir(file)) {
+					step = &versions[i]
+				}
+			}
+			require.NotNil(t, step, "no upgrade for the version of the fixture")
+
+			otelcol := &v1alpha1.OpenTelemetryCollector{Spec: v1alpha1.OpenTelemetryCollectorSpec{Config: fixture.Config}}
+
+			// test
+			res, err := step.apply(nil, otelcol)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, fixture.Expected, res.Spec.Config)
+			assert.Equal(t, fixture.Changes, res.Status.Messages)
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		rule     rule
+		config   string
+		expected string
+		changes  []string
+	}{
+		{
+			desc:     "rename key",
+			rule:     renameKey{components: exporters("logging"), path: []string{"loglevel"}, to: "verbosity"},
+			config:   "exporters:\n  logging:\n    loglevel: debug\n  logging/2:\n",
+			expected: "exporters:\n  logging:\n    verbosity: debug\n  logging/2:\n",
+			changes:  []string{`renamed the property 'loglevel' to 'verbosity' for exporter "logging"`},
+		},
+		{
+			desc:     "move key",
+			rule:     moveKey{components: exporters("otlp"), from: []string{"insecure"}, to: []string{"tls", "insecure"}},
+			config:   "exporters:\n  otlp:\n    insecure: true\n    endpoint: tempo:4317\n",
+			expected: "exporters:\n  otlp:\n    endpoint: tempo:4317\n    tls:\n      insecure: true\n",
+			changes:  []string{`moved the property 'insecure' to 'tls.insecure' for exporter "otlp"`},
+		},
+		{
+			desc:     "move key into an existing mapping",
+			rule:     moveKey{components: exporters("otlp"), from: []string{"insecure"}, to: []string{"tls", "insecure"}},
+			config:   "exporters:\n  otlp:\n    tls:\n      ca_file: ca.pem\n    insecure: true\n",
+			expected: "exporters:\n  otlp:\n    tls:\n      ca_file: ca.pem\n      insecure: true\n",
+			changes:  []string{`moved the property 'insecure' to 'tls.insecure' for exporter "otlp"`},
+		},
+		{
+			desc:     "delete extension",
+			rule:     deleteComponents{components: extensions("pprof")},
+			config:   "extensions:\n  pprof:\n  zpages:\nservice:\n  extensions: [pprof, zpages]\n",
+			expected: "extensions:\n  zpages:\nservice:\n  extensions: [zpages]\n",
+			changes:  []string{`removed the extension "pprof"`, `removed the extension "pprof" from the service`},
+		},
+		{
+			desc:     "set default on an empty property",
+			rule:     setDefault{components: receivers("otlp"), path: []string{"protocols", "http", "endpoint"}, value: "0.0.0.0:55681"},
+			config:   "receivers:\n  otlp:\n    protocols:\n      http: # defaults\n  otlp/2:\n    protocols:\n      grpc:\n  otlp/3:\n",
+			expected: "receivers:\n  otlp:\n    protocols:\n      http: # defaults\n        endpoint: 0.0.0.0:55681\n  otlp/2:\n    protocols:\n      grpc:\n  otlp/3:\n",
+			changes:  []string{`set the property 'protocols.http.endpoint' to its former default 0.0.0.0:55681 for receiver "otlp"`},
+		},
+		{
+			desc:     "set default on an empty component",
+			rule:     setDefault{components: exporters("logging"), path: []string{"verbosity"}, value: "normal"},
+			config:   "exporters:\n  logging:\n  logging/2: {}\n",
+			expected: "exporters:\n  logging:\n    verbosity: normal\n  logging/2: {verbosity: normal}\n",
+			changes:  []string{`set the property 'verbosity' to its former default normal for exporter "logging"`, `set the property 'verbosity' to its former default normal for exporter "logging/2"`},
+		},
+		{
+			desc:     "rename component",
+			rule:     renameComponents{components: exporters("logging"), to: "debug"},
+			config:   "exporters:\n  logging:\n  logging/2:\nservice:\n  pipelines:\n    traces:\n      exporters: [logging/2, otlp]\n",
+			expected: "exporters:\n  debug:\n  debug/2:\nservice:\n  pipelines:\n    traces:\n      exporters: [debug/2, otlp]\n",
+			changes:  []string{`renamed the exporter "logging" to "debug"`, `renamed the exporter "logging/2" to "debug/2"`},
+		},
+		{
+			desc:     "no matching component",
+			rule:     deleteKey{components: exporters("opencensus"), path: []string{"reconnection_delay"}},
+			config:   "exporters:\n  logging:\n    reconnection_delay: 10\n",
+			expected: "exporters:\n  logging:\n    reconnection_delay: 10\n",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			cfg, err := newConfigEditor(tt.config)
+			require.NoError(t, err)
+
+			// test
+			changes, err := tt.rule.apply(cfg)
+			require.NoError(t, err)
+			res, err := cfg.String()
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+			assert.Equal(t, tt.changes, changes)
+		})
+	}
+}
+
+func TestRuleConflicts(t *testing.T) {
+	// prepare
+	cfg, err := newConfigEditor("exporters:\n  logging:\n    loglevel: debug\n    verbosity: normal\n")
+	require.NoError(t, err)
+
+	// test
+	_, err = renameKey{components: exporters("logging"), path: []string{"loglevel"}, to: "verbosity"}.apply(cfg)
+
+	// verify
+	assert.Error(t, err)
+}
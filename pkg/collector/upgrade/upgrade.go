@@ -22,14 +22,24 @@ import (
 
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/go-logr/logr"
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
 	"github.com/open-telemetry/opentelemetry-operator/internal/version"
 )
 
-// ManagedInstances finds all the otelcol instances for the current operator and upgrades them, if necessary.
-func ManagedInstances(ctx context.Context, logger logr.Logger, ver version.Version, cl client.Client) error {
+// maxUpgradeHistory is the number of upgrade steps kept in the status of an instance.
+const maxUpgradeHistory = 10
+
+// unknownVersion is the version of the OpenTelemetry Collector reported by builds that don't set it.
+var unknownVersion = semver.MustParse("0.0.0")
+
+// ManagedInstances finds all the otelcol instances for the current operator and upgrades them, if necessary. The
+// instances whose image follows the operator get the default image for their mode once their configuration is
+// upgraded. When set, inScope tells whether the instances of a namespace are managed by the operator.
+func ManagedInstances(ctx context.Context, logger logr.Logger, ver version.Version, defaultImage func(mode v1alpha1.Mode) string, inScope func(namespace string) bool, cl client.Client) error {
 	logger.Info("looking for managed instances to upgrade")
 
 	opts := []client.ListOption{
@@ -44,11 +54,18 @@ func ManagedInstances(ctx context.Context, logger logr.Logger, ver version.Versi
 
 	for i := range list.Items {
 		original := list.Items[i]
+		if inScope != nil && !inScope(original.Namespace) {
+			logger.V(1).Info("skipping upgrade for OpenTelemetry Collector instance, as its namespace isn't managed by the operator", "name", original.Name, "namespace", original.Namespace)
+			continue
+		}
 		upgraded, err := ManagedInstance(ctx, logger, ver, cl, original)
 		if err != nil {
 			// nothing to do at this level, just go to the next instance
 			continue
 		}
+		if defaultImage != nil {
+			upgraded = followDefaultImage(logger, upgraded, defaultImage(upgraded.Spec.Mode))
+		}
 
 		if !reflect.DeepEqual(upgraded, list.Items[i]) {
 			// the resource update overrides the status, so, keep it so that we can reset it later
@@ -77,13 +94,19 @@ func ManagedInstances(ctx context.Context, logger logr.Logger, ver version.Versi
 	return nil
 }
 
-// ManagedInstance performs the necessary changes to bring the given otelcol instance to the current version.
+// ManagedInstance performs the necessary changes to bring the given otelcol instance to the current version,
+// according to the instance's upgrade policy.
 func ManagedInstance(ctx context.Context, logger logr.Logger, currentV version.Version, cl client.Client, otelcol v1alpha1.OpenTelemetryCollector) (v1alpha1.OpenTelemetryCollector, error) {
 	// this is likely a new instance, assume it's already up to date
 	if otelcol.Status.Version == "" {
 		return otelcol, nil
 	}
 
+	if otelcol.Spec.UpgradePolicy == v1alpha1.UpgradePolicyNone {
+		logger.V(1).Info("skipping upgrade for OpenTelemetry Collector instance, as its upgrade policy is none", "name", otelcol.Name, "namespace", otelcol.Namespace)
+		return otelcol, nil
+	}
+
 	instanceV, err := semver.NewVersion(otelcol.Status.Version)
 	if err != nil {
 		logger.Error(err, "failed to parse version for OpenTelemetry Collector instance", "name", otelcol.Name, "namespace", otelcol.Namespace, "version", otelcol.Status.Version)
@@ -95,24 +118,169 @@ func ManagedInstance(ctx context.Context, logger logr.Logger, currentV version.V
 		return otelcol, nil
 	}
 
+	target, targetV := targetVersion(currentV), currentV.OpenTelemetryCollector
+	if imageV := pinnedVersion(otelcol); imageV != nil && (target == nil || imageV.LessThan(target)) {
+		// the configuration has to remain compatible with the image the instance is pinned to
+		if !imageV.GreaterThan(instanceV) {
+			logger.V(1).Info("skipping upgrade for OpenTelemetry Collector instance, as it's pinned to its image", "name", otelcol.Name, "namespace", otelcol.Namespace, "image", otelcol.Spec.Image)
+			return otelcol, nil
+		}
+		target, targetV = imageV, imageV.String()
+	}
+
+	upgraded, steps, err := runUpgrades(logger, cl, otelcol, instanceV, target)
+	if err != nil {
+		return otelcol, err
+	}
+
+	if otelcol.Spec.UpgradePolicy == v1alpha1.UpgradePolicyManual {
+		return proposeUpgrade(logger, targetV, otelcol, upgraded, steps)
+	}
+
+	if len(changesFrom(steps)) == 0 {
+		// the routines might have rewritten the configuration without changing its meaning, like when the
+		// keys get sorted: the original is kept, so that it still matches the one from the user's sources
+		upgraded.Spec.Config = otelcol.Spec.Config
+	}
+
+	// at the end of the process, we are up to date with the latest known version, which is what we have from versions.txt,
+	// unless the instance is pinned to an older image
+	upgraded.Status.Version = targetV
+	upgraded.Status.PendingUpgrade = nil
+	recordHistory(&upgraded, steps)
+
+	logger.V(1).Info("final version", "name", upgraded.Name, "namespace", upgraded.Namespace, "version", upgraded.Status.Version)
+	return upgraded, nil
+}
+
+// proposeUpgrade records the changes that the upgrade would make in the status of the instance, leaving its
+// configuration untouched. When the configuration requires no changes, like after it has been migrated by hand,
+// the instance is considered upgraded.
+func proposeUpgrade(logger logr.Logger, targetV string, otelcol, upgraded v1alpha1.OpenTelemetryCollector, steps []v1alpha1.UpgradeHistoryEntry) (v1alpha1.OpenTelemetryCollector, error) {
+	changes := changesFrom(steps)
+
+	proposed := otelcol.DeepCopy()
+	if len(changes) == 0 {
+		proposed.Status.Version = targetV
+		proposed.Status.PendingUpgrade = nil
+		recordHistory(proposed, steps)
+		logger.V(1).Info("final version", "name", proposed.Name, "namespace", proposed.Namespace, "version", proposed.Status.Version)
+		return *proposed, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(otelcol.Spec.Config),
+		B:        difflib.SplitLines(upgraded.Spec.Config),
+		FromFile: "current",
+		ToFile:   "proposed",
+		Context:  3,
+	})
+	if err != nil {
+		return otelcol, fmt.Errorf("failed to compare the current and proposed configurations: %w", err)
+	}
+
+	proposed.Status.PendingUpgrade = &v1alpha1.PendingUpgrade{
+		FromVersion: otelcol.Status.Version,
+		ToVersion:   targetV,
+		Changes:     changes,
+		ConfigDiff:  diff,
+	}
+
+	logger.Info("upgrade proposed for OpenTelemetry Collector instance, as its upgrade policy is manual", "name", proposed.Name, "namespace", proposed.Namespace, "version", proposed.Status.Version, "changes", len(changes))
+	return *proposed, nil
+}
+
+// Instance performs the necessary changes to bring the given otelcol instance from the version in its status to the
+// target version, using the same upgrade routines as ManagedInstance. The changes are recorded in the upgrade
+// history. No calls are made to the cluster, so that instances can be upgraded offline.
+func Instance(logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector, target *semver.Version) (v1alpha1.OpenTelemetryCollector, error) {
+	if otelcol.Status.Version == "" {
+		return otelcol, fmt.Errorf("the version of the OpenTelemetry Collector instance %q is unknown", otelcol.Name)
+	}
+
+	instanceV, err := semver.NewVersion(otelcol.Status.Version)
+	if err != nil {
+		return otelcol, fmt.Errorf("failed to parse the version of the OpenTelemetry Collector instance %q: %w", otelcol.Name, err)
+	}
+
+	upgraded, steps, err := runUpgrades(logger, nil, otelcol, instanceV, target)
+	if err != nil {
+		return otelcol, err
+	}
+
+	if target.GreaterThan(instanceV) {
+		upgraded.Status.Version = target.String()
+	}
+	recordHistory(&upgraded, steps)
+
+	return upgraded, nil
+}
+
+// runUpgrades runs the upgrade routines for the versions newer than the instance's version, up to the target version
+// when it's set, returning the upgraded instance along with the steps that have been applied.
+func runUpgrades(logger logr.Logger, cl client.Client, otelcol v1alpha1.OpenTelemetryCollector, instanceV, target *semver.Version) (v1alpha1.OpenTelemetryCollector, []v1alpha1.UpgradeHistoryEntry, error) {
+	otelcol = *otelcol.DeepCopy()
+
+	// the routines report their changes as messages, which are moved to the history entry of each step
+	messages := otelcol.Status.Messages
+
+	steps := []v1alpha1.UpgradeHistoryEntry{}
 	for _, available := range versions {
+		if target != nil && available.GreaterThan(target) {
+			break
+		}
+
 		if available.GreaterThan(instanceV) {
-			upgraded, err := available.upgrade(cl, &otelcol)
+			from := otelcol.Status.Version
+			otelcol.Status.Messages = nil
+			upgraded, err := available.apply(cl, &otelcol)
 
 			if err != nil {
 				logger.Error(err, "failed to upgrade managed otelcol instances", "name", otelcol.Name, "namespace", otelcol.Namespace)
-				return otelcol, err
+				return otelcol, steps, err
 			}
 
 			logger.V(1).Info("step upgrade", "name", otelcol.Name, "namespace", otelcol.Namespace, "version", available.String())
+			steps = append(steps, v1alpha1.UpgradeHistoryEntry{
+				FromVersion: from,
+				ToVersion:   available.String(),
+				Timestamp:   metav1.Now(),
+				Changes:     upgraded.Status.Messages,
+			})
+			upgraded.Status.Messages = messages
 			upgraded.Status.Version = available.String()
 			otelcol = *upgraded
 		}
 	}
 
-	// at the end of the process, we are up to date with the latest known version, which is what we have from versions.txt
-	otelcol.Status.Version = currentV.OpenTelemetryCollector
+	return otelcol, steps, nil
+}
+
+// targetVersion returns the version the managed instances are upgraded to, which is the version of the OpenTelemetry
+// Collector deployed by the operator: the upgrades for newer versions would make the configuration incompatible with
+// it. When the version is unknown, like in development builds, all the upgrades are applied.
+func targetVersion(currentV version.Version) *semver.Version {
+	target, err := semver.NewVersion(currentV.OpenTelemetryCollector)
+	if err != nil || target.Equal(unknownVersion) {
+		return nil
+	}
+	return target
+}
+
+// changesFrom returns the changes made by all the given upgrade steps.
+func changesFrom(steps []v1alpha1.UpgradeHistoryEntry) []string {
+	changes := []string{}
+	for _, step := range steps {
+		changes = append(changes, step.Changes...)
+	}
+	return changes
+}
 
-	logger.V(1).Info("final version", "name", otelcol.Name, "namespace", otelcol.Namespace, "version", otelcol.Status.Version)
-	return otelcol, nil
+// recordHistory adds the upgrade steps to the instance's history, keeping only the most recent entries.
+func recordHistory(otelcol *v1alpha1.OpenTelemetryCollector, steps []v1alpha1.UpgradeHistoryEntry) {
+	history := append(otelcol.Status.UpgradeHistory, steps...)
+	if len(history) > maxUpgradeHistory {
+		history = history[len(history)-maxUpgradeHistory:]
+	}
+	otelcol.Status.UpgradeHistory = history
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"testing"
 
+	semver "github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,7 +61,7 @@ func TestShouldUpgradeAllToLatest(t *testing.T) {
 	require.Equal(t, "0.0.1", persisted.Status.Version)
 
 	// test
-	err = upgrade.ManagedInstances(context.Background(), logger, currentV, k8sClient)
+	err = upgrade.ManagedInstances(context.Background(), logger, currentV, nil, nil, k8sClient)
 	assert.NoError(t, err)
 
 	// verify
@@ -97,6 +98,80 @@ func TestUpgradeUpToLatestKnownVersion(t *testing.T) {
 	assert.Equal(t, "0.10.0", res.Status.Version)
 }
 
+func TestUpgradeUpToTheOperatorVersion(t *testing.T) {
+	// prepare
+	existing := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: `receivers:
+  otlp:
+    protocols:
+      http:
+`,
+		},
+	}
+	existing.Status.Version = "0.24.0"
+
+	currentV := version.Get()
+	currentV.OpenTelemetryCollector = "0.29.0" // the upgrade to 0.31.0 would set the former default port for OTLP HTTP
+
+	// test
+	res, err := upgrade.ManagedInstance(context.Background(), logger, currentV, nil, existing)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, existing.Spec.Config, res.Spec.Config)
+	assert.Equal(t, "0.29.0", res.Status.Version)
+}
+
+func TestUpgradeUpToThePinnedImage(t *testing.T) {
+	config := `receivers:
+  otlp:
+    protocols:
+      http:
+`
+	currentV := version.Get()
+	currentV.OpenTelemetryCollector = "0.86.0"
+
+	for _, tt := range []struct {
+		desc            string
+		image           string
+		recorded        string
+		strategy        v1alpha1.UpgradeStrategy
+		expectedVersion string
+		expectedChanges int
+	}{
+		{"explicit image", "otel/opentelemetry-collector:0.29.0", "", v1alpha1.UpgradeStrategyAutomatic, "0.29.0", 0},
+		{"recorded image with strategy none", "otel/opentelemetry-collector:0.31.0", "otel/opentelemetry-collector:0.31.0", v1alpha1.UpgradeStrategyNone, "0.31.0", 1},
+		{"recorded image with strategy automatic", "otel/opentelemetry-collector:0.29.0", "otel/opentelemetry-collector:0.29.0", v1alpha1.UpgradeStrategyAutomatic, "0.86.0", 1},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			existing := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-instance",
+					Namespace:   "default",
+					Annotations: map[string]string{v1alpha1.AnnotationDefaultImage: tt.recorded},
+				},
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Config:          config,
+					Image:           tt.image,
+					UpgradeStrategy: tt.strategy,
+				},
+			}
+			existing.Status.Version = "0.29.0"
+
+			// test
+			res, err := upgrade.ManagedInstance(context.Background(), logger, currentV, nil, existing)
+
+			// verify
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, res.Status.Version)
+			assert.Len(t, changes(res), tt.expectedChanges)
+		})
+	}
+}
+
 func TestVersionsShouldNotBeChanged(t *testing.T) {
 	for _, tt := range []struct {
 		desc            string
@@ -138,3 +213,161 @@ func TestVersionsShouldNotBeChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestUpgradeInstanceUpToTargetVersion(t *testing.T) {
+	// prepare
+	existing := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-instance",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: `exporters:
+  opencensus:
+    reconnection_delay: 10s
+extensions:
+  health_check:
+    port: 13133
+`,
+		},
+	}
+	existing.Status.Version = "0.8.0"
+
+	for _, tt := range []struct {
+		target   string
+		messages int
+	}{
+		{"0.9.0", 1},
+		{"0.29.0", 2},
+		{"0.8.0", 0},
+	} {
+		t.Run(tt.target, func(t *testing.T) {
+			// test
+			upgraded, err := upgrade.Instance(logger, *existing.DeepCopy(), semver.MustParse(tt.target))
+
+			// verify
+			require.NoError(t, err)
+			assert.Len(t, changes(upgraded), tt.messages)
+			assert.Equal(t, tt.target, upgraded.Status.Version)
+		})
+	}
+}
+
+func TestUpgradeInstanceWithoutVersion(t *testing.T) {
+	// test
+	_, err := upgrade.Instance(logger, v1alpha1.OpenTelemetryCollector{}, semver.MustParse("0.29.0"))
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestUpgradePolicies(t *testing.T) {
+	config := `extensions:
+  health_check:
+    port: 13133
+`
+	currentV := version.Get()
+	currentV.OpenTelemetryCollector = "0.29.0"
+
+	for _, tt := range []struct {
+		policy          v1alpha1.UpgradePolicy
+		expectedConfig  string
+		expectedVersion string
+		expectedPending bool
+	}{
+		{v1alpha1.UpgradePolicyAutomatic, "endpoint: 0.0.0.0:13133", "0.29.0", false},
+		{v1alpha1.UpgradePolicyManual, "port: 13133", "0.20.0", true},
+		{v1alpha1.UpgradePolicyNone, "port: 13133", "0.20.0", false},
+	} {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			// prepare
+			existing := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					Config:        config,
+					UpgradePolicy: tt.policy,
+				},
+			}
+			existing.Status.Version = "0.20.0"
+
+			// test
+			res, err := upgrade.ManagedInstance(context.Background(), logger, currentV, nil, existing)
+
+			// verify
+			require.NoError(t, err)
+			assert.Contains(t, res.Spec.Config, tt.expectedConfig)
+			assert.Equal(t, tt.expectedVersion, res.Status.Version)
+			if tt.expectedPending {
+				require.NotNil(t, res.Status.PendingUpgrade)
+				assert.Equal(t, "0.20.0", res.Status.PendingUpgrade.FromVersion)
+				assert.Equal(t, "0.29.0", res.Status.PendingUpgrade.ToVersion)
+				assert.Len(t, res.Status.PendingUpgrade.Changes, 1)
+				assert.Contains(t, res.Status.PendingUpgrade.ConfigDiff, "+    endpoint: 0.0.0.0:13133")
+				assert.Empty(t, res.Status.UpgradeHistory)
+			} else {
+				assert.Nil(t, res.Status.PendingUpgrade)
+			}
+		})
+	}
+}
+
+func TestManualUpgradeOfMigratedConfig(t *testing.T) {
+	// prepare
+	existing := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			// already migrated by hand, and with the keys in a different order than the one the routines produce
+			Config: `extensions:
+  health_check:
+    endpoint: 0.0.0.0:13133
+exporters:
+  logging:
+`,
+			UpgradePolicy: v1alpha1.UpgradePolicyManual,
+		},
+	}
+	existing.Status.Version = "0.20.0"
+	existing.Status.PendingUpgrade = &v1alpha1.PendingUpgrade{FromVersion: "0.20.0"}
+	currentV := version.Get()
+	currentV.OpenTelemetryCollector = "0.29.0"
+
+	// test
+	res, err := upgrade.ManagedInstance(context.Background(), logger, currentV, nil, existing)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, existing.Spec.Config, res.Spec.Config)
+	assert.Equal(t, "0.29.0", res.Status.Version)
+	assert.Nil(t, res.Status.PendingUpgrade)
+	require.Len(t, res.Status.UpgradeHistory, 1)
+	assert.Equal(t, "0.20.0", res.Status.UpgradeHistory[0].FromVersion)
+	assert.Equal(t, "0.24.0", res.Status.UpgradeHistory[0].ToVersion)
+}
+
+func TestUpgradeHistoryIsBounded(t *testing.T) {
+	// prepare
+	existing := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+	}
+	existing.Status.Version = "0.0.1"
+	for i := 0; i < 10; i++ {
+		existing.Status.UpgradeHistory = append(existing.Status.UpgradeHistory, v1alpha1.UpgradeHistoryEntry{FromVersion: "0.0.0", ToVersion: "0.0.1"})
+	}
+
+	// test
+	res, err := upgrade.ManagedInstance(context.Background(), logger, version.Get(), nil, existing)
+
+	// verify
+	require.NoError(t, err)
+	assert.Len(t, res.Status.UpgradeHistory, 10)
+	assert.Equal(t, upgrade.Latest.String(), res.Status.UpgradeHistory[9].ToVersion)
+}
+
+// changes returns all the changes recorded in the upgrade history of the instance.
+func changes(otelcol v1alpha1.OpenTelemetryCollector) []string {
+	changes := []string{}
+	for _, entry := range otelcol.Status.UpgradeHistory {
+		changes = append(changes, entry.Changes...)
+	}
+	return changes
+}
@@ -18,11 +18,10 @@ import (
 	"fmt"
 	"strings"
 
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
-	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/adapters"
 )
 
 func upgrade0_19_0(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (*v1alpha1.OpenTelemetryCollector, error) {
@@ -30,92 +29,84 @@ func upgrade0_19_0(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (
 		return otelcol, nil
 	}
 
-	cfg, err := adapters.ConfigFromString(otelcol.Spec.Config)
+	cfg, err := newConfigEditor(otelcol.Spec.Config)
 	if err != nil {
-		return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, failed to parse configuration: %w", err)
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, %w", err)
 	}
 
-	processors, ok := cfg["processors"].(map[interface{}]interface{})
-	if !ok {
+	processors := mapping(cfg.root, "processors")
+	if processors == nil {
 		// no processors? no need to fail because of that
 		return otelcol, nil
 	}
 
-	for k, v := range processors {
+	for _, k := range keys(processors) {
 		// from the changelog https://github.com/open-telemetry/opentelemetry-collector/releases/tag/v0.19.0
-
-		// Remove deprecated queued_retry processor
-		if strings.HasPrefix(k.(string), "queued_retry") {
-			delete(processors, k)
-			otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.19.0 removed the processor %q", k))
-			continue
-		}
+		// the queued_retry processor is removed by a rule, and the resource processor requires a custom migration
 
 		// Remove deprecated configs from resource processor: type (set "opencensus.type" key in "attributes.upsert" map instead) and labels (use "attributes.upsert" instead).
-		if strings.HasPrefix(k.(string), "resource") {
-			switch processor := v.(type) {
-			case map[interface{}]interface{}:
-				// type becomes an attribute.upsert with key opencensus.type
-				if typ, found := processor["type"]; found {
-					var attributes []map[string]string
-					if attrs, found := processor["attributes"]; found {
-						if attributes, ok = attrs.([]map[string]string); !ok {
-							return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, the attributes list for processors %q couldn't be parsed based on the previous value. Type: %t, value: %v", k, attrs, attrs)
-						}
+		if strings.HasPrefix(k.Value, "resource") {
+			switch processor := lookup(processors, k.Value); processor.Kind {
+			case yaml.MappingNode:
+				attributes := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+				if attrs := lookup(processor, "attributes"); attrs != nil {
+					if attrs.Kind != yaml.SequenceNode {
+						return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, the attributes list for processors %q couldn't be parsed based on the previous value", k.Value)
 					}
-					attr := map[string]string{}
-					attr["key"] = "opencensus.type"
-					attr["value"] = typ.(string)
-					attr["action"] = "upsert"
-					attributes = append(attributes, attr)
-
-					processor["attributes"] = attributes
-					delete(processor, "type")
-					otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.19.0 migrated the property 'type' for processor %q", k))
+					attributes.Content = append(attributes.Content, attrs.Content...)
 				}
 
-				// handle labels
-				if labels, found := processor["labels"]; found {
-					var attributes []map[string]string
-					if attrs, found := processor["attributes"]; found {
-						if attributes, ok = attrs.([]map[string]string); !ok {
-							return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, the attributes list for processors %q couldn't be parsed based on the previous value. Type: %t, value: %v", k, attrs, attrs)
-						}
-					}
+				var migrated []string
+
+				// type becomes an attribute.upsert with key opencensus.type
+				if typ := lookup(processor, "type"); typ != nil {
+					attributes.Content = append(attributes.Content, upsertAttribute("opencensus.type", typ.Value))
+					migrated = append(migrated, "type")
+				}
 
-					if ls, ok := labels.(map[interface{}]interface{}); ok {
-						for labelK, labelV := range ls {
-							attr := map[string]string{}
-							attr["key"] = labelK.(string)
-							attr["value"] = labelV.(string)
-							attr["action"] = "upsert"
-							attributes = append(attributes, attr)
+				// handle labels
+				if labels := lookup(processor, "labels"); labels != nil {
+					if labels.Kind == yaml.MappingNode {
+						for i := 0; i+1 < len(labels.Content); i += 2 {
+							attributes.Content = append(attributes.Content, upsertAttribute(labels.Content[i].Value, resolve(labels.Content[i+1]).Value))
 						}
 					}
-
-					processor["attributes"] = attributes
-					delete(processor, "labels")
-					otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.19.0 migrated the property 'labels' for processor %q", k))
+					migrated = append(migrated, "labels")
 				}
 
-				processors[k] = processor
-			case string:
-				if len(processor) == 0 {
-					// this processor is using the default configuration
+				if len(migrated) == 0 {
 					continue
 				}
+
+				// the attributes are set before the migrated properties are removed, so that they take their place
+				// when they are the last ones of the processor
+				cfg.set(processor, "attributes", attributes)
+				for _, property := range migrated {
+					cfg.remove(processor, property)
+					otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.19.0 migrated the property '%s' for processor %q", property, k.Value))
+				}
+			case yaml.ScalarNode:
+				// this processor is using the default configuration
+				continue
 			default:
-				return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, the processor %q is invalid (neither a string nor map)", k)
+				return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, the processor %q is invalid (neither a string nor map)", k.Value)
 			}
 		}
 	}
 
-	cfg["processors"] = processors
-	res, err := yaml.Marshal(cfg)
+	res, err := cfg.String()
 	if err != nil {
-		return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, failed to marshall back configuration: %w", err)
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.19.0, %w", err)
 	}
 
-	otelcol.Spec.Config = string(res)
+	otelcol.Spec.Config = res
 	return otelcol, nil
 }
+
+func upsertAttribute(key, value string) *yaml.Node {
+	return mappingNode(
+		scalarNode("key"), scalarNode(key),
+		scalarNode("value"), scalarNode(value),
+		scalarNode("action"), scalarNode("upsert"),
+	)
+}
@@ -66,7 +66,7 @@ func TestRemoveQueuedRetryProcessor(t *testing.T) {
 	assert.Contains(t, res.Spec.Config, "otherprocessor:")
 	assert.NotContains(t, res.Spec.Config, "queued_retry/second:")
 	assert.NotContains(t, res.Spec.Config, "num_workers: 123") // checking one property is sufficient
-	assert.Contains(t, res.Status.Messages[0], "upgrade to v0.19.0 removed the processor")
+	assert.Contains(t, changes(res)[0], "upgrade to v0.19.0 removed the processor")
 }
 
 func TestMigrateResourceType(t *testing.T) {
@@ -97,11 +97,11 @@ func TestMigrateResourceType(t *testing.T) {
 	assert.Equal(t, `processors:
   resource:
     attributes:
-    - action: upsert
-      key: opencensus.type
-      value: some-type
+      - key: opencensus.type
+        value: some-type
+        action: upsert
 `, res.Spec.Config)
-	assert.Contains(t, res.Status.Messages[0], "upgrade to v0.19.0 migrated the property 'type' for processor")
+	assert.Contains(t, changes(res)[0], "upgrade to v0.19.0 migrated the property 'type' for processor")
 }
 
 func TestMigrateLabels(t *testing.T) {
@@ -139,5 +139,5 @@ func TestMigrateLabels(t *testing.T) {
 	// verify
 	assert.Len(t, actualAttrs, 2)
 	assert.Nil(t, actualProcessor["labels"])
-	assert.Contains(t, res.Status.Messages[0], "upgrade to v0.19.0 migrated the property 'labels' for processor")
+	assert.Contains(t, changes(res)[0], "upgrade to v0.19.0 migrated the property 'labels' for processor")
 }
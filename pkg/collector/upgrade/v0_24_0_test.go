@@ -57,12 +57,12 @@ func TestHealthCheckEndpointMigration(t *testing.T) {
 
 	// verify
 	assert.Equal(t, `extensions:
-  health_check: null
+  health_check:
   health_check/1: ""
   health_check/2:
-    endpoint: localhost:13133
+    endpoint: "localhost:13133"
   health_check/3:
     endpoint: 0.0.0.0:13133
 `, res.Spec.Config)
-	assert.Equal(t, "upgrade to v0.24.0 migrated the property 'port' to 'endpoint' for extension \"health_check/3\"", res.Status.Messages[0])
+	assert.Equal(t, "upgrade to v0.24.0 migrated the property 'port' to 'endpoint' for extension \"health_check/3\"", changes(res)[0])
 }
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+// upgrade0_39_0 moves the memory ballast, removed from the memory_limiter processor and from the command line
+// arguments, to the memory_ballast extension.
+func upgrade0_39_0(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (*v1alpha1.OpenTelemetryCollector, error) {
+	var size string
+	if arg, ok := otelcol.Spec.Args["mem-ballast-size-mib"]; ok {
+		size = arg
+		delete(otelcol.Spec.Args, "mem-ballast-size-mib")
+		otelcol.Status.Messages = append(otelcol.Status.Messages, "upgrade to v0.39.0 removed the argument --mem-ballast-size-mib")
+	}
+
+	if len(otelcol.Spec.Config) == 0 {
+		return otelcol, nil
+	}
+
+	cfg, err := newConfigEditor(otelcol.Spec.Config)
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.39.0, %w", err)
+	}
+	if cfg.root == nil {
+		return otelcol, nil
+	}
+
+	err = processors("memory_limiter").eachMapping(cfg, func(name string, processor *yaml.Node) error {
+		ballast := lookup(processor, "ballast_size_mib")
+		if ballast == nil {
+			return nil
+		}
+		if len(size) == 0 {
+			size = ballast.Value
+		}
+		cfg.remove(processor, "ballast_size_mib")
+		otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.39.0 removed the property ballast_size_mib for processor %q", name))
+		return nil
+	})
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.39.0, %w", err)
+	}
+
+	if len(size) > 0 {
+		if err := addMemoryBallast(cfg, size); err != nil {
+			return otelcol, fmt.Errorf("couldn't upgrade to v0.39.0, %w", err)
+		}
+		otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.39.0 moved the memory ballast of %s MiB to the extension \"memory_ballast\"", size))
+	}
+
+	res, err := cfg.String()
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.39.0, %w", err)
+	}
+
+	otelcol.Spec.Config = res
+	return otelcol, nil
+}
+
+// addMemoryBallast adds the memory_ballast extension with the given size to the configuration, unless it's already
+// there, and enables it in the service.
+func addMemoryBallast(cfg *configEditor, size string) error {
+	extensions := cfg.ensureMapping(cfg.root, "extensions")
+	if extensions == nil {
+		return fmt.Errorf("the extensions are invalid (not a map)")
+	}
+	if lookup(extensions, "memory_ballast") == nil {
+		cfg.set(extensions, "memory_ballast", mappingNode(scalarNode("size_mib"), &yaml.Node{Kind: yaml.ScalarNode, Value: size}))
+	}
+
+	service := cfg.ensureMapping(cfg.root, "service")
+	if service == nil {
+		return fmt.Errorf("the service is invalid (not a map)")
+	}
+	enabled := lookup(service, "extensions")
+	switch {
+	case enabled == nil:
+		cfg.set(service, "extensions", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: []*yaml.Node{scalarNode("memory_ballast")}})
+	case enabled.Kind == yaml.SequenceNode:
+		for _, item := range enabled.Content {
+			if item.Value == "memory_ballast" {
+				return nil
+			}
+		}
+		cfg.appendItem(enabled, scalarNode("memory_ballast"))
+	default:
+		return fmt.Errorf("the extensions of the service are invalid (not a list)")
+	}
+	return nil
+}
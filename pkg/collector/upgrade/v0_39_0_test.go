@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade_test
+
+import (
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/pkg/collector/upgrade"
+)
+
+func TestMemoryBallastFromArgs(t *testing.T) {
+	// prepare
+	existing := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-instance", Namespace: "default"},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Args: map[string]string{"mem-ballast-size-mib": "683"},
+			Config: `processors:
+  memory_limiter:
+    limit_mib: 1500
+`,
+		},
+	}
+	existing.Status.Version = "0.38.0"
+
+	// test
+	res, err := upgrade.Instance(logger, existing, semver.MustParse("0.39.0"))
+
+	// verify
+	require.NoError(t, err)
+	assert.NotContains(t, res.Spec.Args, "mem-ballast-size-mib")
+	assert.Equal(t, `processors:
+  memory_limiter:
+    limit_mib: 1500
+extensions:
+  memory_ballast:
+    size_mib: 683
+service:
+  extensions: [memory_ballast]
+`, res.Spec.Config)
+	assert.Len(t, changes(res), 2)
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+)
+
+const (
+	jaegerGRPCPort = ":14250"
+	jaegerOTLPPort = ":4317"
+)
+
+// upgrade0_85_0 replaces the jaeger exporters, which have been removed from the collector, by OTLP exporters, as
+// Jaeger accepts OTLP natively. The endpoints using the Jaeger gRPC port are changed to the OTLP gRPC port.
+func upgrade0_85_0(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (*v1alpha1.OpenTelemetryCollector, error) {
+	if len(otelcol.Spec.Config) == 0 {
+		return otelcol, nil
+	}
+
+	cfg, err := newConfigEditor(otelcol.Spec.Config)
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.85.0, %w", err)
+	}
+
+	err = exporters("jaeger").each(cfg, func(section *yaml.Node, name string) error {
+		if exporter := lookup(section, name); exporter.Kind == yaml.MappingNode {
+			if endpoint := lookup(exporter, "endpoint"); endpoint != nil && strings.HasSuffix(endpoint.Value, jaegerGRPCPort) {
+				cfg.set(exporter, "endpoint", scalarNode(strings.TrimSuffix(endpoint.Value, jaegerGRPCPort)+jaegerOTLPPort))
+				otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.85.0 changed the port of the endpoint for exporter %q from 14250 to 4317", name))
+			}
+		}
+
+		renamed := "otlp/" + strings.Replace(name, "/", "_", 1)
+		if err := renameComponent(cfg, "exporters", name, renamed); err != nil {
+			return err
+		}
+		otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v0.85.0 replaced the removed exporter %q by the OTLP exporter %q", name, renamed))
+		return nil
+	})
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.85.0, %w", err)
+	}
+
+	res, err := cfg.String()
+	if err != nil {
+		return otelcol, fmt.Errorf("couldn't upgrade to v0.85.0, %w", err)
+	}
+
+	otelcol.Spec.Config = res
+	return otelcol, nil
+}
@@ -61,5 +61,5 @@ func TestRemoveConnectionDelay(t *testing.T) {
 	assert.Contains(t, res.Spec.Config, `compression: "on"`)
 	assert.NotContains(t, res.Spec.Config, "reconnection_delay")
 	assert.Contains(t, res.Spec.Config, "num_workers: 123")
-	assert.Contains(t, res.Status.Messages[0], "upgrade to v0.9.0 removed the property reconnection_delay for exporter")
+	assert.Contains(t, changes(res)[0], "upgrade to v0.9.0 removed the property reconnection_delay for exporter")
 }
@@ -15,6 +15,8 @@
 package upgrade
 
 import (
+	"fmt"
+
 	"github.com/Masterminds/semver/v3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -23,8 +25,12 @@ import (
 
 type upgradeFunc func(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (*v1alpha1.OpenTelemetryCollector, error)
 
+// otelcolVersion describes the changes to make to an instance when upgrading to a version: first the rules are
+// applied to the configuration, and then the upgrade function, if any, is called for the changes that can't be
+// expressed as rules.
 type otelcolVersion struct {
 	semver.Version
+	rules   []rule
 	upgrade upgradeFunc
 }
 
@@ -36,7 +42,9 @@ var (
 		},
 		{
 			Version: *semver.MustParse("0.9.0"),
-			upgrade: upgrade0_9_0,
+			rules: []rule{
+				deleteKey{components: exporters("opencensus"), path: []string{"reconnection_delay"}},
+			},
 		},
 		{
 			Version: *semver.MustParse("0.15.0"),
@@ -44,14 +52,92 @@ var (
 		},
 		{
 			Version: *semver.MustParse("0.19.0"),
+			rules: []rule{
+				// from the changelog https://github.com/open-telemetry/opentelemetry-collector/releases/tag/v0.19.0
+				deleteComponents{components: processors("queued_retry")},
+			},
 			upgrade: upgrade0_19_0,
 		},
 		{
 			Version: *semver.MustParse("0.24.0"),
-			upgrade: upgrade0_24_0,
+			rules: []rule{
+				portToEndpoint{components: extensions("health_check"), path: []string{"port"}, endpoint: "endpoint", host: "0.0.0.0"},
+			},
+		},
+		{
+			// the default port for OTLP over HTTP changed from 55681 to 4318
+			Version: *semver.MustParse("0.31.0"),
+			rules: []rule{
+				setDefault{components: receivers("otlp"), path: []string{"protocols", "http", "endpoint"}, value: "0.0.0.0:55681"},
+			},
+		},
+		{
+			// the TLS settings of the receivers and exporters are now under "tls"
+			Version: *semver.MustParse("0.36.0"),
+			rules: []rule{
+				renameKey{components: receivers("otlp"), path: []string{"protocols", "grpc", "tls_settings"}, to: "tls"},
+				renameKey{components: receivers("otlp"), path: []string{"protocols", "http", "tls_settings"}, to: "tls"},
+				moveKey{components: exporters("otlp"), from: []string{"insecure"}, to: []string{"tls", "insecure"}},
+				moveKey{components: exporters("jaeger"), from: []string{"insecure"}, to: []string{"tls", "insecure"}},
+				moveKey{components: exporters("opencensus"), from: []string{"insecure"}, to: []string{"tls", "insecure"}},
+			},
+		},
+		{
+			Version: *semver.MustParse("0.39.0"),
+			upgrade: upgrade0_39_0,
+		},
+		{
+			Version: *semver.MustParse("0.85.0"),
+			upgrade: upgrade0_85_0,
+		},
+		{
+			// the logging exporter is deprecated in favor of the debug exporter
+			Version: *semver.MustParse("0.86.0"),
+			rules: []rule{
+				renameKey{components: exporters("logging"), path: []string{"loglevel"}, to: "verbosity", values: map[string]string{
+					"debug": "detailed",
+					"info":  "normal",
+					"warn":  "basic",
+					"error": "basic",
+				}},
+				// the debug exporter defaults to a lower verbosity than the logging exporter
+				setDefault{components: exporters("logging"), path: []string{"verbosity"}, value: "normal"},
+				renameComponents{components: exporters("logging"), to: "debug"},
+			},
 		},
 	}
 
 	// Latest represents the latest version that we need to upgrade. This is not necessarily the latest known version.
 	Latest = versions[len(versions)-1]
 )
+
+// apply makes the changes for the upgrade to this version.
+func (v otelcolVersion) apply(cl client.Client, otelcol *v1alpha1.OpenTelemetryCollector) (*v1alpha1.OpenTelemetryCollector, error) {
+	if len(v.rules) > 0 && len(otelcol.Spec.Config) > 0 {
+		cfg, err := newConfigEditor(otelcol.Spec.Config)
+		if err != nil {
+			return otelcol, fmt.Errorf("couldn't upgrade to v%s, %w", v.String(), err)
+		}
+
+		for _, r := range v.rules {
+			changes, err := r.apply(cfg)
+			if err != nil {
+				return otelcol, fmt.Errorf("couldn't upgrade to v%s, %w", v.String(), err)
+			}
+			for _, change := range changes {
+				otelcol.Status.Messages = append(otelcol.Status.Messages, fmt.Sprintf("upgrade to v%s %s", v.String(), change))
+			}
+		}
+
+		res, err := cfg.String()
+		if err != nil {
+			return otelcol, fmt.Errorf("couldn't upgrade to v%s, %w", v.String(), err)
+		}
+		otelcol.Spec.Config = res
+	}
+
+	if v.upgrade == nil {
+		return otelcol, nil
+	}
+	return v.upgrade(cl, otelcol)
+}
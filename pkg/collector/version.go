@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	"github.com/open-telemetry/opentelemetry-operator/internal/version"
+)
+
+// TargetVersion returns the version of the OpenTelemetry Collector the instance targets: the version of its image, as
+// that's the one running once the instance is reconciled, or, when the image has no version tag, the version reported
+// in its status or, for new instances, the version deployed by default. It returns nil when the version can't be
+// parsed.
+func TargetVersion(otelcol v1alpha1.OpenTelemetryCollector) *semver.Version {
+	if imageV := ImageVersion(otelcol.Spec.Image); imageV != nil {
+		return imageV
+	}
+
+	v := otelcol.Status.Version
+	if len(v) == 0 {
+		v = version.OpenTelemetryCollector()
+	}
+
+	target, err := semver.NewVersion(v)
+	if err != nil {
+		return nil
+	}
+	return target
+}
+
+// ImageVersion returns the OpenTelemetry Collector version from the tag of the given image, like 0.29.0 for
+// otel/opentelemetry-collector:0.29.0. It returns nil when the image has no tag or when the tag isn't a version.
+func ImageVersion(image string) *semver.Version {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	// the registry might have a port, so, the tag is only what's after the last colon of the last path element
+	i := strings.LastIndex(image, ":")
+	if i < 0 || i < strings.LastIndex(image, "/") {
+		return nil
+	}
+
+	v, err := semver.NewVersion(image[i+1:])
+	if err != nil {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
+	. "github.com/open-telemetry/opentelemetry-operator/pkg/collector"
+)
+
+func TestTargetVersion(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		version  string
+		image    string
+		expected string
+	}{
+		{"image", "0.31.0", "otel/opentelemetry-collector:0.29.0", "0.29.0"},
+		{"upgraded", "0.31.0", "", "0.31.0"},
+		{"image without a version", "0.31.0", "otel/opentelemetry-collector:latest", "0.31.0"},
+		{"new instance", "", "", "0.0.0"},
+		{"new instance with an image", "", "otel/opentelemetry-collector:0.29.0", "0.29.0"},
+		{"unparseable", "latest", "", ""},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			otelcol := v1alpha1.OpenTelemetryCollector{}
+			otelcol.Spec.Image = tt.image
+			otelcol.Status.Version = tt.version
+
+			// test
+			v := TargetVersion(otelcol)
+
+			// verify
+			if len(tt.expected) == 0 {
+				assert.Nil(t, v)
+			} else {
+				assert.Equal(t, tt.expected, v.String())
+			}
+		})
+	}
+}
+
+func TestImageVersion(t *testing.T) {
+	for _, tt := range []struct {
+		image    string
+		expected string
+	}{
+		{"otel/opentelemetry-collector:0.29.0", "0.29.0"},
+		{"otel/opentelemetry-collector-contrib:v0.31.0", "0.31.0"},
+		{"registry:5000/otel/opentelemetry-collector:0.31.0@sha256:1234", "0.31.0"},
+		{"registry:5000/otel/opentelemetry-collector", ""},
+		{"otel/opentelemetry-collector:latest", ""},
+		{"", ""},
+	} {
+		t.Run(tt.image, func(t *testing.T) {
+			// test
+			v := ImageVersion(tt.image)
+
+			// verify
+			if len(tt.expected) == 0 {
+				assert.Nil(t, v)
+			} else {
+				assert.Equal(t, tt.expected, v.String())
+			}
+		})
+	}
+}
@@ -38,6 +38,10 @@ func Volumes(cfg config.Config, otelcol v1alpha1.OpenTelemetryCollector) []corev
 		},
 	}}
 
+	if otelcol.Spec.TLS != nil {
+		volumes = append(volumes, TLSVolume(otelcol))
+	}
+
 	if len(otelcol.Spec.Volumes) > 0 {
 		volumes = append(volumes, otelcol.Spec.Volumes...)
 	}
@@ -31,6 +31,11 @@ func ConfigMapVolume() string {
 	return "otc-internal"
 }
 
+// TLSVolume returns the name to use for the volume with the receivers' certificate in the pod.
+func TLSVolume() string {
+	return "otc-tls"
+}
+
 // Container returns the name to use for the container in the pod.
 func Container() string {
 	return "otc-container"
@@ -51,6 +56,11 @@ func MonitoringService(otelcol v1alpha1.OpenTelemetryCollector) string {
 	return fmt.Sprintf("%s-monitoring", Service(otelcol))
 }
 
+// Route builds the name for the OpenShift route exposing the given port of the instance.
+func Route(otelcol v1alpha1.OpenTelemetryCollector, port string) string {
+	return fmt.Sprintf("%s-collector-%s", otelcol.Name, port)
+}
+
 // Service builds the service name based on the instance.
 func Service(otelcol v1alpha1.OpenTelemetryCollector) string {
 	return fmt.Sprintf("%s-collector", otelcol.Name)
@@ -60,3 +70,23 @@ func Service(otelcol v1alpha1.OpenTelemetryCollector) string {
 func ServiceAccount(otelcol v1alpha1.OpenTelemetryCollector) string {
 	return fmt.Sprintf("%s-collector", otelcol.Name)
 }
+
+// TLSSecret builds the name for the secret with the receivers' serving certificate based on the instance.
+func TLSSecret(otelcol v1alpha1.OpenTelemetryCollector) string {
+	return fmt.Sprintf("%s-collector-tls", otelcol.Name)
+}
+
+// CASecret builds the name for the secret with the certificate authority issuing the receivers' certificates.
+func CASecret(otelcol v1alpha1.OpenTelemetryCollector) string {
+	return fmt.Sprintf("%s-collector-ca", otelcol.Name)
+}
+
+// SelfSignedIssuer builds the name for the cert-manager issuer of the instance's certificate authority.
+func SelfSignedIssuer(otelcol v1alpha1.OpenTelemetryCollector) string {
+	return fmt.Sprintf("%s-collector-selfsigned", otelcol.Name)
+}
+
+// CAIssuer builds the name for the cert-manager issuer of the receivers' certificates.
+func CAIssuer(otelcol v1alpha1.OpenTelemetryCollector) string {
+	return fmt.Sprintf("%s-collector-ca", otelcol.Name)
+}
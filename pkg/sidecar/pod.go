@@ -16,7 +16,9 @@
 package sidecar
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -31,13 +33,27 @@ const (
 	label = "sidecar.opentelemetry.io/injected"
 )
 
+var (
+	// ErrPortClash indicates that the ports of the sidecar are already used by the pod's containers.
+	ErrPortClash = errors.New("the ports of the sidecar are already used by the pod's containers")
+
+	// ErrInvalidOverlay indicates that the pod template overlay of the instance can't be applied to the sidecar.
+	ErrInvalidOverlay = errors.New("invalid pod template overlay")
+)
+
 // Add a new sidecar container to the given pod, based on the given OpenTelemetryCollector.
 func Add(cfg config.Config, logger logr.Logger, otelcol v1alpha1.OpenTelemetryCollector, pod corev1.Pod) (corev1.Pod, error) {
-	// add the container
-	volumes := collector.Volumes(cfg, otelcol)
-	container := collector.Container(cfg, logger, otelcol)
+	// add the container, along with the changes from the overlay, which can't touch the rest of the application's pod
+	container, volumes, err := collector.ApplySidecarOverlay(otelcol, collector.Container(cfg, logger, otelcol), collector.Volumes(cfg, otelcol))
+	if err != nil {
+		return pod, fmt.Errorf("%w: %s", ErrInvalidOverlay, err)
+	}
+	if clashes := portClashes(container, pod); len(clashes) > 0 {
+		return pod, fmt.Errorf("%w: %s", ErrPortClash, strings.Join(clashes, ", "))
+	}
 	pod.Spec.Containers = append(pod.Spec.Containers, container)
 	pod.Spec.Volumes = append(pod.Spec.Volumes, volumes...)
+	pod.Spec.ImagePullSecrets = collector.AppendImagePullSecrets(pod.Spec.ImagePullSecrets, collector.ImagePullSecrets(cfg, otelcol))
 
 	if pod.Labels == nil {
 		pod.Labels = map[string]string{}
@@ -63,6 +79,29 @@ func Remove(pod corev1.Pod) (corev1.Pod, error) {
 	return pod, nil
 }
 
+// portClashes describes the ports of the given container already used by the pod's containers, which share the
+// pod's network namespace.
+func portClashes(container corev1.Container, pod corev1.Pod) []string {
+	var clashes []string
+	for _, port := range container.Ports {
+		for _, other := range pod.Spec.Containers {
+			for _, otherPort := range other.Ports {
+				if otherPort.ContainerPort == port.ContainerPort && protocol(otherPort) == protocol(port) {
+					clashes = append(clashes, fmt.Sprintf("%d/%s used by the container %q", port.ContainerPort, protocol(port), other.Name))
+				}
+			}
+		}
+	}
+	return clashes
+}
+
+func protocol(port corev1.ContainerPort) corev1.Protocol {
+	if len(port.Protocol) == 0 {
+		return corev1.ProtocolTCP
+	}
+	return port.Protocol
+}
+
 // ExistsIn checks whether a sidecar container exists in the given pod.
 func ExistsIn(pod corev1.Pod) bool {
 	for _, container := range pod.Spec.Containers {
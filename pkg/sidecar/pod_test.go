@@ -15,11 +15,13 @@
 package sidecar_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/open-telemetry/opentelemetry-operator/api/v1alpha1"
@@ -59,6 +61,184 @@ func TestAddSidecarWhenNoSidecarExists(t *testing.T) {
 	assert.Equal(t, "some-app.otelcol-sample", changed.Labels["sidecar.opentelemetry.io/injected"])
 }
 
+func TestAddSidecarWithImagePullSecrets(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers:       []corev1.Container{{Name: "my-app"}},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "my-app-registry"}},
+		},
+	}
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otelcol-sample",
+			Namespace: "some-app",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "my-app-registry"}, {Name: "otelcol-registry"}},
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "my-app-registry"}, {Name: "otelcol-registry"}}, changed.Spec.ImagePullSecrets)
+}
+
+func TestAddSidecarWithPodTemplateOverlay(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "my-app"}},
+		},
+	}
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otelcol-sample",
+			Namespace: "some-app",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			PodTemplateOverlay: &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": [{"name": "otc-container", "env": [{"name": "GOGC", "value": "80"}], "volumeMounts": [{"name": "extra", "mountPath": "/extra"}]}], "volumes": [{"name": "extra", "emptyDir": {}}]}}`)},
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, changed.Spec.Containers, 2)
+	assert.Equal(t, pod.Spec.Containers[0], changed.Spec.Containers[0])
+	assert.Equal(t, []corev1.EnvVar{{Name: "GOGC", Value: "80"}}, changed.Spec.Containers[1].Env)
+	assert.Contains(t, changed.Spec.Containers[1].VolumeMounts, corev1.VolumeMount{Name: "extra", MountPath: "/extra"})
+	assert.Contains(t, changed.Spec.Volumes, corev1.Volume{Name: "extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+	assert.Equal(t, "some-app.otelcol-sample", changed.Labels["sidecar.opentelemetry.io/injected"])
+}
+
+func TestAddSidecarWithPodLevelOverlay(t *testing.T) {
+	for _, tt := range []struct {
+		desc    string
+		overlay string
+	}{
+		{"pod field", `{"spec": {"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["backend"]}]}}`},
+		{"application container", `{"spec": {"containers": [{"name": "my-app", "image": "other"}]}}`},
+		{"metadata", `{"metadata": {"labels": {"my-label": "my-value"}}}`},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			pod := corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "my-app"}},
+				},
+			}
+			otelcol := v1alpha1.OpenTelemetryCollector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "otelcol-sample",
+					Namespace: "some-app",
+				},
+				Spec: v1alpha1.OpenTelemetryCollectorSpec{
+					PodTemplateOverlay: &runtime.RawExtension{Raw: []byte(tt.overlay)},
+				},
+			}
+			cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+			// test
+			changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+			// verify
+			assert.True(t, errors.Is(err, sidecar.ErrInvalidOverlay))
+			assert.Equal(t, pod, changed)
+		})
+	}
+}
+
+func TestAddSidecarWithPortClash(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "my-app",
+				Ports: []corev1.ContainerPort{{ContainerPort: 8888}},
+			}},
+		},
+	}
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otelcol-sample",
+			Namespace: "some-app",
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+	// verify
+	assert.True(t, errors.Is(err, sidecar.ErrPortClash))
+	assert.Contains(t, err.Error(), `8888/TCP used by the container "my-app"`)
+	assert.Equal(t, pod, changed)
+}
+
+func TestAddSidecarWithMetricsOnAnotherPort(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "my-app",
+				Ports: []corev1.ContainerPort{{ContainerPort: 8888}},
+			}},
+		},
+	}
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otelcol-sample",
+			Namespace: "some-app",
+		},
+		Spec: v1alpha1.OpenTelemetryCollectorSpec{
+			Config: "service:\n  telemetry:\n    metrics:\n      address: 0.0.0.0:8889\n",
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, changed.Spec.Containers, 2)
+	assert.Equal(t, int32(8889), changed.Spec.Containers[1].Ports[0].ContainerPort)
+}
+
+func TestAddSidecarWithDifferentProtocol(t *testing.T) {
+	// prepare
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "my-app",
+				Ports: []corev1.ContainerPort{{ContainerPort: 8888, Protocol: corev1.ProtocolUDP}},
+			}},
+		},
+	}
+	otelcol := v1alpha1.OpenTelemetryCollector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otelcol-sample",
+			Namespace: "some-app",
+		},
+	}
+	cfg := config.New(config.WithCollectorImage("some-default-image"))
+
+	// test
+	changed, err := sidecar.Add(cfg, logger, otelcol, pod)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Len(t, changed.Spec.Containers, 2)
+}
+
 // this situation should never happen in the current code path, but it should not fail
 // if it's asked to add a new sidecar. The caller is expected to have called ExistsIn before.
 func TestAddSidecarWhenOneExistsAlready(t *testing.T) {